@@ -0,0 +1,144 @@
+package olympian
+
+import "fmt"
+
+type Blueprint struct {
+	tb *TableBuilder
+}
+
+func (bp *Blueprint) Uuid(name string) *ColumnBuilder {
+	return bp.tb.addColumn(name, "uuid")
+}
+
+func (bp *Blueprint) String(name string, length ...int) *ColumnBuilder {
+	return bp.tb.addString(name, "string", length)
+}
+
+func (bp *Blueprint) Char(name string, length int) *ColumnBuilder {
+	return bp.tb.addString(name, "char", []int{length})
+}
+
+func (bp *Blueprint) Text(name string) *ColumnBuilder {
+	return bp.tb.addColumn(name, "text")
+}
+
+func (bp *Blueprint) Integer(name string) *ColumnBuilder {
+	return bp.tb.addColumn(name, "integer")
+}
+
+func (bp *Blueprint) BigInteger(name string) *ColumnBuilder {
+	return bp.tb.addColumn(name, "bigint")
+}
+
+func (bp *Blueprint) Boolean(name string) *ColumnBuilder {
+	return bp.tb.addColumn(name, "boolean")
+}
+
+func (bp *Blueprint) Decimal(name string, precision, scale int) *ColumnBuilder {
+	return bp.tb.addColumn(name, fmt.Sprintf("decimal(%d,%d)", precision, scale))
+}
+
+func (bp *Blueprint) Timestamp(name string) *ColumnBuilder {
+	return bp.tb.addColumn(name, "timestamp")
+}
+
+func (bp *Blueprint) Date(name string) *ColumnBuilder {
+	return bp.tb.addColumn(name, "date")
+}
+
+func (bp *Blueprint) Json(name string) *ColumnBuilder {
+	return bp.tb.addColumn(name, "json")
+}
+
+func (bp *Blueprint) Jsonb(name string) *ColumnBuilder {
+	return bp.tb.addColumn(name, "jsonb")
+}
+
+func (bp *Blueprint) SmallInteger(name string) *ColumnBuilder {
+	return bp.tb.addColumn(name, "smallint")
+}
+
+func (bp *Blueprint) TinyInteger(name string) *ColumnBuilder {
+	return bp.tb.addColumn(name, "tinyint")
+}
+
+func (bp *Blueprint) Float(name string) *ColumnBuilder {
+	return bp.tb.addColumn(name, "float")
+}
+
+func (bp *Blueprint) Double(name string) *ColumnBuilder {
+	return bp.tb.addColumn(name, "double")
+}
+
+func (bp *Blueprint) MediumText(name string) *ColumnBuilder {
+	return bp.tb.addColumn(name, "mediumtext")
+}
+
+func (bp *Blueprint) LongText(name string) *ColumnBuilder {
+	return bp.tb.addColumn(name, "longtext")
+}
+
+func (bp *Blueprint) Binary(name string, length ...int) *ColumnBuilder {
+	return bp.tb.addString(name, "binary", length)
+}
+
+func (bp *Blueprint) Blob(name string) *ColumnBuilder {
+	return bp.tb.addColumn(name, "blob")
+}
+
+func (bp *Blueprint) Time(name string) *ColumnBuilder {
+	return bp.tb.addColumn(name, "time")
+}
+
+func (bp *Blueprint) DateTime(name string) *ColumnBuilder {
+	return bp.tb.addColumn(name, "datetime")
+}
+
+func (bp *Blueprint) Year(name string) *ColumnBuilder {
+	return bp.tb.addColumn(name, "year")
+}
+
+func (bp *Blueprint) IpAddress(name string) *ColumnBuilder {
+	return bp.tb.addColumn(name, "ipaddress")
+}
+
+func (bp *Blueprint) MacAddress(name string) *ColumnBuilder {
+	return bp.tb.addColumn(name, "macaddress")
+}
+
+func (bp *Blueprint) Enum(name string, values []string) *ColumnBuilder {
+	return bp.tb.addEnum(name, values)
+}
+
+func (bp *Blueprint) PrimaryKey(columns ...string) {
+	bp.tb.setPrimaryKey(columns)
+}
+
+func (bp *Blueprint) Timestamps() {
+	bp.Timestamp("created_at").Nullable()
+	bp.Timestamp("updated_at").Nullable()
+}
+
+func (bp *Blueprint) SoftDeletes() {
+	bp.Timestamp("deleted_at").Nullable()
+}
+
+func (bp *Blueprint) Foreign(columns ...string) *ForeignKeyBuilder {
+	return bp.tb.addForeign(columns)
+}
+
+func (bp *Blueprint) Index(columns ...string) *IndexBuilder {
+	return bp.tb.addIndex(columns, false)
+}
+
+func (bp *Blueprint) UniqueIndex(columns ...string) *IndexBuilder {
+	return bp.tb.addIndex(columns, true)
+}
+
+func (bp *Blueprint) Check(name, expression string) {
+	bp.tb.addCheck(name, expression)
+}
+
+func (bp *Blueprint) DropCheck(name string) {
+	bp.tb.dropCheck(name)
+}
@@ -0,0 +1,151 @@
+package olympian
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestBlueprintCreate(t *testing.T) {
+	db := setupTestDB(t)
+	defer func() { _ = db.Close() }()
+
+	SetDB(db, &SQLiteDialect{})
+
+	err := Table("users").CreateWith(func(t *Blueprint) {
+		t.Uuid("id").Primary()
+		t.String("name")
+		t.Integer("age").Nullable()
+		t.Timestamps()
+	})
+	if err != nil {
+		t.Fatalf("Failed to create table: %v", err)
+	}
+
+	var count int
+	err = db.QueryRow("SELECT COUNT(*) FROM pragma_table_info('users')").Scan(&count)
+	if err != nil {
+		t.Fatalf("Failed to query table info: %v", err)
+	}
+
+	if count != 5 {
+		t.Errorf("Expected 5 columns, got %d", count)
+	}
+}
+
+func TestBlueprintConcurrentBuilders(t *testing.T) {
+	var wg sync.WaitGroup
+	builders := make([]*TableBuilder, 50)
+
+	for i := range builders {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			tb := &TableBuilder{tableName: fmt.Sprintf("table_%d", i)}
+			tb.build(func(t *Blueprint) {
+				t.Uuid("id").Primary()
+				for j := 0; j < i%5; j++ {
+					t.String(fmt.Sprintf("col_%d", j))
+				}
+				t.Foreign("owner_id").References("id").On("owners")
+			})
+			builders[i] = tb
+		}(i)
+	}
+	wg.Wait()
+
+	for i, tb := range builders {
+		if len(tb.columns) != 1+i%5 {
+			t.Errorf("Builder %d: expected %d columns, got %d", i, 1+i%5, len(tb.columns))
+		}
+		if len(tb.foreignKeys) != 1 {
+			t.Errorf("Builder %d: expected 1 foreign key, got %d", i, len(tb.foreignKeys))
+		}
+	}
+}
+
+func TestSharedBuildersDoNotInterleave(t *testing.T) {
+	var wg sync.WaitGroup
+	builders := make([]*TableBuilder, 50)
+
+	for i := range builders {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			tb := &TableBuilder{tableName: fmt.Sprintf("table_%d", i)}
+			tb.build(shared(func() {
+				Uuid("id").Primary()
+				for j := 0; j < i%5; j++ {
+					String(fmt.Sprintf("col_%d", j))
+				}
+			}))
+			builders[i] = tb
+		}(i)
+	}
+	wg.Wait()
+
+	for i, tb := range builders {
+		if len(tb.columns) != 1+i%5 {
+			t.Errorf("Builder %d: expected %d columns, got %d", i, 1+i%5, len(tb.columns))
+		}
+	}
+	if currentBuilder != nil {
+		t.Error("Expected currentBuilder to be cleared after building")
+	}
+}
+
+func TestCreateWithAlongsidePretend(t *testing.T) {
+	pretendDB := setupTestDB(t)
+	defer func() { _ = pretendDB.Close() }()
+	db := setupTestDB(t)
+	defer func() { _ = db.Close() }()
+	db.SetMaxOpenConns(1)
+
+	migrator := NewMigrator(pretendDB, &SQLiteDialect{})
+	if err := migrator.Init(); err != nil {
+		t.Fatalf("Failed to initialize migrator: %v", err)
+	}
+	migrations := []Migration{{
+		Name: "1_create_posts_table",
+		Up: func() error {
+			return Table("posts").CreateWith(func(t *Blueprint) {
+				t.Uuid("id").Primary()
+			})
+		},
+	}}
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 20; i++ {
+			plans, err := migrator.Pretend(migrations)
+			if err != nil {
+				t.Errorf("Failed to pretend: %v", err)
+				return
+			}
+			if len(plans) != 1 || len(plans[0].Operations) != 1 || plans[0].Operations[0].Table != "posts" {
+				t.Errorf("Expected only the posts table to be recorded, got %+v", plans)
+				return
+			}
+		}
+	}()
+
+	for i := 0; i < 20; i++ {
+		err := TableOn(db, &SQLiteDialect{}, fmt.Sprintf("table_%d", i)).CreateWith(func(t *Blueprint) {
+			t.Uuid("id").Primary()
+		})
+		if err != nil {
+			t.Fatalf("Failed to create table_%d: %v", i, err)
+		}
+	}
+	wg.Wait()
+
+	tables, err := (&SQLiteDialect{}).GetTables(db)
+	if err != nil {
+		t.Fatalf("Failed to get tables: %v", err)
+	}
+	if len(tables) != 20 {
+		t.Errorf("Expected 20 tables, got %v", tables)
+	}
+}
@@ -0,0 +1,60 @@
+package olympian
+
+import "fmt"
+
+// tableCheck is a named CHECK constraint declared inside a Create or Modify
+// closure.
+type tableCheck struct {
+	name       string
+	expression string
+}
+
+func (tb *TableBuilder) addCheck(name, expression string) {
+	if tb != nil {
+		tb.checks = append(tb.checks, &tableCheck{name: name, expression: expression})
+	}
+}
+
+func (tb *TableBuilder) dropCheck(name string) {
+	if tb != nil {
+		tb.droppedChecks = append(tb.droppedChecks, name)
+	}
+}
+
+// Check declares a named table-level CHECK constraint. Inside Modify it is
+// added to the existing table.
+func Check(name, expression string) {
+	currentBuilder.addCheck(name, expression)
+}
+
+// DropCheck drops a named CHECK constraint. It is only valid inside Modify.
+func DropCheck(name string) {
+	currentBuilder.dropCheck(name)
+}
+
+// Check adds an unnamed CHECK constraint to the column definition.
+func (cb *ColumnBuilder) Check(expression string) *ColumnBuilder {
+	cb.column.check = expression
+	return cb
+}
+
+func checkDefinition(d Dialect, check *tableCheck) string {
+	return fmt.Sprintf("CONSTRAINT %s CHECK (%s)", d.QuoteIdentifier(check.name), check.expression)
+}
+
+func (tb *TableBuilder) validateChecks() error {
+	for _, check := range tb.checks {
+		if check.name == "" || check.expression == "" {
+			return fmt.Errorf("table %s: CHECK constraints need a name and an expression", tb.tableName)
+		}
+	}
+	if tb.operation == "create" && len(tb.droppedChecks) > 0 {
+		return fmt.Errorf("table %s: DropCheck is only supported inside Modify", tb.tableName)
+	}
+	for _, col := range tb.columns {
+		if col.change && col.check != "" {
+			return fmt.Errorf("column %s.%s: Check cannot be combined with Change, use Check(name, expression) instead", tb.tableName, col.name)
+		}
+	}
+	return nil
+}
@@ -8,7 +8,10 @@ import (
 	"github.com/spf13/cobra"
 )
 
+var initForce bool
+
 func init() {
+	initCmd.Flags().BoolVar(&initForce, "force", false, "Overwrite an existing cmd/migrate/main.go")
 	rootCmd.AddCommand(initCmd)
 }
 
@@ -63,110 +66,11 @@ func runInit(cmd *cobra.Command, args []string) error {
 	mainGoPath := filepath.Join(migrateDir, "main.go")
 
 	// Check if file already exists
-	if _, err := os.Stat(mainGoPath); err == nil {
-		return fmt.Errorf("cmd/migrate/main.go already exists")
-	}
-
-	template := fmt.Sprintf(`package main
-
-import (
-	"database/sql"
-	"fmt"
-	"log"
-	"os"
-
-	_ "github.com/go-sql-driver/mysql"
-	_ "github.com/lib/pq"
-	_ "github.com/mattn/go-sqlite3"
-	"github.com/ichtrojan/olympian"
-	"github.com/joho/godotenv"
-
-	_ "%s/migrations"
-)
-
-func main() {
-	if err := godotenv.Load(); err != nil {
-		log.Println("No .env file found")
-	}
-
-	dbDriver := os.Getenv("DB_DRIVER")
-	dbHost := os.Getenv("DB_HOST")
-	dbPort := os.Getenv("DB_PORT")
-	dbName := os.Getenv("DB_NAME")
-	dbUser := os.Getenv("DB_USER")
-	dbPass := os.Getenv("DB_PASS")
-
-	if dbDriver == "" {
-		log.Fatal("DB_DRIVER not set in .env")
+	if _, err := os.Stat(mainGoPath); err == nil && !initForce {
+		return fmt.Errorf("cmd/migrate/main.go already exists, use --force to regenerate it")
 	}
 
-	var dsn string
-	var dialect olympian.Dialect
-
-	switch dbDriver {
-	case "mysql":
-		dsn = fmt.Sprintf("%%s:%%s@tcp(%%s:%%s)/%%s?parseTime=true", dbUser, dbPass, dbHost, dbPort, dbName)
-		dialect = olympian.MySQL()
-	case "postgres":
-		dsn = fmt.Sprintf("host=%%s port=%%s user=%%s password=%%s dbname=%%s sslmode=disable", dbHost, dbPort, dbUser, dbPass, dbName)
-		dialect = olympian.Postgres()
-	case "sqlite3":
-		dsn = os.Getenv("DB_DSN")
-		if dsn == "" {
-			dsn = "./database.db"
-		}
-		dialect = olympian.SQLite()
-	default:
-		log.Fatalf("Unsupported database driver: %%s", dbDriver)
-	}
-
-	db, err := sql.Open(dbDriver, dsn)
-	if err != nil {
-		log.Fatalf("Failed to connect to database: %%v", err)
-	}
-	defer db.Close()
-
-	migrator := olympian.NewMigrator(db, dialect)
-	if err := migrator.Init(); err != nil {
-		log.Fatalf("Failed to initialize migrator: %%v", err)
-	}
-
-	migrations := olympian.GetMigrations()
-
-	if len(os.Args) > 1 {
-		switch os.Args[1] {
-		case "status":
-			if err := migrator.Status(migrations); err != nil {
-				log.Fatalf("Failed to get status: %%v", err)
-			}
-		case "rollback":
-			if err := migrator.Rollback(migrations, 1); err != nil {
-				log.Fatalf("Failed to rollback: %%v", err)
-			}
-			fmt.Println("Rollback completed successfully")
-		case "reset":
-			if err := migrator.Reset(migrations); err != nil {
-				log.Fatalf("Failed to reset: %%v", err)
-			}
-			fmt.Println("Reset completed successfully")
-		case "fresh":
-			if err := migrator.Fresh(migrations); err != nil {
-				log.Fatalf("Failed to fresh: %%v", err)
-			}
-			fmt.Println("Fresh migration completed successfully")
-		default:
-			fmt.Printf("Unknown command: %%s\n", os.Args[1])
-			fmt.Println("Available commands: migrate (default), status, rollback, reset, fresh")
-			os.Exit(1)
-		}
-	} else {
-		if err := migrator.Migrate(migrations); err != nil {
-			log.Fatalf("Failed to run migrations: %%v", err)
-		}
-		fmt.Println("Migrations completed successfully")
-	}
-}
-`, moduleName)
+	template := migrateMainSource(moduleName)
 
 	if err := os.WriteFile(mainGoPath, []byte(template), 0644); err != nil {
 		return fmt.Errorf("failed to write main.go: %w", err)
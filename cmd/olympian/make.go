@@ -0,0 +1,164 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ichtrojan/olympian"
+	"github.com/spf13/cobra"
+)
+
+var (
+	fromModel  string
+	modelTable string
+)
+
+func init() {
+	makeCmd.PersistentFlags().StringVar(&migrationPath, "path", "./migrations", "Path to migrations directory")
+	makeMigrationCmd.Flags().StringVar(&fromModel, "from-model", "", "Generate the migration from a Go struct (e.g. models.User)")
+	makeMigrationCmd.Flags().StringVar(&modelTable, "table", "", "Table name (defaults to the pluralized snake_case type name)")
+
+	makeCmd.AddCommand(makeMigrationCmd)
+
+	rootCmd.AddCommand(makeCmd)
+}
+
+var makeCmd = &cobra.Command{
+	Use:   "make",
+	Short: "Generate files",
+}
+
+var makeMigrationCmd = &cobra.Command{
+	Use:   "migration [name]",
+	Short: "Create a new migration file",
+	Long: `Creates a new migration file. With --from-model, the struct fields and their
+olympian:"..." tags are turned into columns: a create-table migration for a new
+table, or a Modify migration with only the new fields for an existing one.
+
+Supported tag options: type:<type>, column:<name>, nullable, unique, primary,
+default:<value>, fk:<table>.<column>, ondelete:<action>. Use olympian:"-" to
+skip a field.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var name string
+		if len(args) > 0 {
+			name = args[0]
+		}
+
+		if fromModel == "" {
+			if name == "" {
+				return fmt.Errorf("a migration name or --from-model is required")
+			}
+			return createMigration(name)
+		}
+		return createModelMigration(fromModel, modelTable, name)
+	},
+}
+
+func createModelMigration(ref, table, name string) error {
+	dir, typeName, err := resolveModel(ref)
+	if err != nil {
+		return err
+	}
+
+	fields, err := parseModel(dir, typeName)
+	if err != nil {
+		return err
+	}
+	if len(fields) == 0 {
+		return fmt.Errorf("%s has no exported fields", ref)
+	}
+
+	if table == "" {
+		table = pluralize(snakeCase(typeName))
+	}
+
+	if migrationPath == "" {
+		migrationPath = "./migrations"
+	}
+	if err := os.MkdirAll(migrationPath, 0755); err != nil {
+		return fmt.Errorf("failed to create migrations directory: %w", err)
+	}
+
+	tables, err := existingColumns(migrationPath)
+	if err != nil {
+		return err
+	}
+
+	existing, create := tables[table], tables[table] == nil
+	if !create {
+		var added []modelField
+		for _, f := range fields {
+			if !existing[f.column] {
+				added = append(added, f)
+			}
+		}
+		if len(added) == 0 {
+			return fmt.Errorf("table %s already has every field of %s", table, ref)
+		}
+		fields = added
+	}
+
+	if name == "" {
+		if create {
+			name = fmt.Sprintf("create_%s_table", table)
+		} else {
+			name = fmt.Sprintf("add_%s_to_%s_table", fields[0].column, table)
+		}
+	}
+
+	migrationName := fmt.Sprintf("%d_%s", olympian.GetTimestamp(), name)
+	source, err := modelMigrationSource(migrationName, table, fields, create)
+	if err != nil {
+		return fmt.Errorf("failed to generate migration: %w", err)
+	}
+
+	filePath := filepath.Join(migrationPath, migrationName+".go")
+	if err := os.WriteFile(filePath, source, 0644); err != nil {
+		return fmt.Errorf("failed to create migration file: %w", err)
+	}
+
+	fmt.Printf("Created migration: %s\n", filePath)
+	return nil
+}
+
+// resolveModel splits pkg.Type into the package directory and type name. The
+// package may be a directory relative to the project root or an import path
+// within the current module.
+func resolveModel(ref string) (string, string, error) {
+	i := strings.LastIndex(ref, ".")
+	if i <= 0 || i == len(ref)-1 {
+		return "", "", fmt.Errorf("expected --from-model in the form pkg.Type, got %q", ref)
+	}
+	pkg, typeName := ref[:i], ref[i+1:]
+
+	if module := readModuleName("go.mod"); module != "" {
+		if pkg == module {
+			pkg = "."
+		} else if strings.HasPrefix(pkg, module+"/") {
+			pkg = strings.TrimPrefix(pkg, module+"/")
+		}
+	}
+
+	dir := filepath.FromSlash(pkg)
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		return "", "", fmt.Errorf("package directory %s not found", dir)
+	}
+	return dir, typeName, nil
+}
+
+func readModuleName(goModPath string) string {
+	content, err := os.ReadFile(goModPath)
+	if err != nil {
+		return ""
+	}
+	for _, line := range strings.Split(string(content), "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "module ") {
+			return strings.TrimSpace(strings.TrimPrefix(line, "module "))
+		}
+	}
+	return ""
+}
@@ -5,6 +5,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
 
 	"github.com/ichtrojan/olympian"
 	"github.com/spf13/cobra"
@@ -15,6 +16,7 @@ var (
 	dbDsn         string
 	migrationPath string
 	useEnv        bool
+	lintSeverity  []string
 )
 
 func init() {
@@ -29,6 +31,9 @@ func init() {
 	migrateCmd.AddCommand(migrateResetCmd)
 	migrateCmd.AddCommand(migrateFreshCmd)
 	migrateCmd.AddCommand(migrateCreateCmd)
+	migrateCmd.AddCommand(migrateLintCmd)
+
+	migrateLintCmd.Flags().StringArrayVar(&lintSeverity, "severity", nil, "Override a rule's severity as rule=off|warning|error (repeatable)")
 
 	rootCmd.AddCommand(migrateCmd)
 }
@@ -77,6 +82,28 @@ var migrateFreshCmd = &cobra.Command{
 	},
 }
 
+var migrateLintCmd = &cobra.Command{
+	Use:   "lint",
+	Short: "Check pending migrations for risky operations",
+	Long: `Runs pending migrations in pretend mode and flags risky operations:
+
+  not-null-without-default    NOT NULL column added in Modify without a default (error)
+  drop-column                 column dropped from a non-empty table (error)
+  drop-table                  non-empty table dropped (error)
+  index-without-concurrently  Postgres index created without CONCURRENTLY (warning)
+  type-narrowing              column changed to a narrower type (error)
+
+Exits with a non-zero status when an error-level issue is found.`,
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if _, err := olympian.ParseLintSeverities(lintSeverity); err != nil {
+			return err
+		}
+		return runWithCmdMigrate("lint", lintSeverity...)
+	},
+}
+
 var migrateCreateCmd = &cobra.Command{
 	Use:   "create [name]",
 	Short: "Create a new migration file",
@@ -90,7 +117,7 @@ func runMigrate(cmd *cobra.Command, args []string) error {
 	return runWithCmdMigrate("migrate")
 }
 
-func runWithCmdMigrate(command string) error {
+func runWithCmdMigrate(command string, args ...string) error {
 	// Check if cmd/migrate/main.go exists
 	if _, err := os.Stat("cmd/migrate/main.go"); err != nil {
 		// Doesn't exist - create it automatically
@@ -102,13 +129,17 @@ func runWithCmdMigrate(command string) error {
 		fmt.Println()
 	}
 
+	if err := checkMigrateFile(command); err != nil {
+		return err
+	}
+
 	// Use the existing cmd/migrate/main.go
 	var runCmd *exec.Cmd
 	if command == "migrate" {
 		// No argument needed for migrate - it's the default
 		runCmd = exec.Command("go", "run", "cmd/migrate/main.go")
 	} else {
-		runCmd = exec.Command("go", "run", "cmd/migrate/main.go", command)
+		runCmd = exec.Command("go", append([]string{"run", "cmd/migrate/main.go", command}, args...)...)
 	}
 	runCmd.Stdout = os.Stdout
 	runCmd.Stderr = os.Stderr
@@ -116,6 +147,22 @@ func runWithCmdMigrate(command string) error {
 	return runCmd.Run()
 }
 
+// checkMigrateFile catches a cmd/migrate/main.go generated by an older
+// version, which does not know newer commands such as diff and lint.
+func checkMigrateFile(command string) error {
+	if command == "migrate" {
+		return nil
+	}
+	source, err := os.ReadFile("cmd/migrate/main.go")
+	if err != nil {
+		return fmt.Errorf("failed to read cmd/migrate/main.go: %w", err)
+	}
+	if !strings.Contains(string(source), fmt.Sprintf("case %q:", command)) {
+		return fmt.Errorf("cmd/migrate/main.go has no %s command, it was generated by an older version of olympian; regenerate it with 'olympian init --force' (this overwrites changes made to it)", command)
+	}
+	return nil
+}
+
 func initializeMigrateFile() error {
 	// Get current working directory
 	cwd, err := os.Getwd()
@@ -158,106 +205,7 @@ func initializeMigrateFile() error {
 	// Create main.go
 	mainGoPath := filepath.Join(migrateDir, "main.go")
 
-	template := fmt.Sprintf(`package main
-
-import (
-	"database/sql"
-	"fmt"
-	"log"
-	"os"
-
-	_ "github.com/go-sql-driver/mysql"
-	_ "github.com/lib/pq"
-	_ "github.com/mattn/go-sqlite3"
-	"github.com/ichtrojan/olympian"
-	"github.com/joho/godotenv"
-
-	_ "%s/migrations"
-)
-
-func main() {
-	if err := godotenv.Load(); err != nil {
-		log.Println("No .env file found")
-	}
-
-	dbDriver := os.Getenv("DB_DRIVER")
-	dbHost := os.Getenv("DB_HOST")
-	dbPort := os.Getenv("DB_PORT")
-	dbName := os.Getenv("DB_NAME")
-	dbUser := os.Getenv("DB_USER")
-	dbPass := os.Getenv("DB_PASS")
-
-	if dbDriver == "" {
-		log.Fatal("DB_DRIVER not set in .env")
-	}
-
-	var dsn string
-	var dialect olympian.Dialect
-
-	switch dbDriver {
-	case "mysql":
-		dsn = fmt.Sprintf("%%s:%%s@tcp(%%s:%%s)/%%s?parseTime=true", dbUser, dbPass, dbHost, dbPort, dbName)
-		dialect = olympian.MySQL()
-	case "postgres":
-		dsn = fmt.Sprintf("host=%%s port=%%s user=%%s password=%%s dbname=%%s sslmode=disable", dbHost, dbPort, dbUser, dbPass, dbName)
-		dialect = olympian.Postgres()
-	case "sqlite3":
-		dsn = os.Getenv("DB_DSN")
-		if dsn == "" {
-			dsn = "./database.db"
-		}
-		dialect = olympian.SQLite()
-	default:
-		log.Fatalf("Unsupported database driver: %%s", dbDriver)
-	}
-
-	db, err := sql.Open(dbDriver, dsn)
-	if err != nil {
-		log.Fatalf("Failed to connect to database: %%v", err)
-	}
-	defer db.Close()
-
-	migrator := olympian.NewMigrator(db, dialect)
-	if err := migrator.Init(); err != nil {
-		log.Fatalf("Failed to initialize migrator: %%v", err)
-	}
-
-	migrations := olympian.GetMigrations()
-
-	if len(os.Args) > 1 {
-		switch os.Args[1] {
-		case "status":
-			if err := migrator.Status(migrations); err != nil {
-				log.Fatalf("Failed to get status: %%v", err)
-			}
-		case "rollback":
-			if err := migrator.Rollback(migrations, 1); err != nil {
-				log.Fatalf("Failed to rollback: %%v", err)
-			}
-			fmt.Println("Rollback completed successfully")
-		case "reset":
-			if err := migrator.Reset(migrations); err != nil {
-				log.Fatalf("Failed to reset: %%v", err)
-			}
-			fmt.Println("Reset completed successfully")
-		case "fresh":
-			if err := migrator.Fresh(migrations); err != nil {
-				log.Fatalf("Failed to fresh: %%v", err)
-			}
-			fmt.Println("Fresh migration completed successfully")
-		default:
-			fmt.Printf("Unknown command: %%s\n", os.Args[1])
-			fmt.Println("Available commands: migrate (default), status, rollback, reset, fresh")
-			os.Exit(1)
-		}
-	} else {
-		if err := migrator.Migrate(migrations); err != nil {
-			log.Fatalf("Failed to run migrations: %%v", err)
-		}
-		fmt.Println("Migrations completed successfully")
-	}
-}
-`, moduleName)
+	template := migrateMainSource(moduleName)
 
 	return os.WriteFile(mainGoPath, []byte(template), 0644)
 }
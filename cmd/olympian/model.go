@@ -0,0 +1,583 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+type modelField struct {
+	column    string
+	builder   string
+	args      []string
+	nullable  bool
+	primary   bool
+	unique    bool
+	dflt      string
+	refTable  string
+	refColumn string
+	onDelete  string
+}
+
+var (
+	decimalTagPattern = regexp.MustCompile(`^decimal\((\d+),\s*(\d+)\)$`)
+	lengthTagPattern  = regexp.MustCompile(`^(string|char|binary)\((\d+)\)$`)
+)
+
+// parseModel finds the struct typeName in the package at dir and turns its
+// fields into column definitions.
+func parseModel(dir, typeName string) ([]modelField, error) {
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, dir, func(info os.FileInfo) bool {
+		return !strings.HasSuffix(info.Name(), "_test.go")
+	}, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", dir, err)
+	}
+
+	for _, pkg := range pkgs {
+		for _, file := range pkg.Files {
+			for _, decl := range file.Decls {
+				gen, ok := decl.(*ast.GenDecl)
+				if !ok || gen.Tok != token.TYPE {
+					continue
+				}
+				for _, spec := range gen.Specs {
+					ts := spec.(*ast.TypeSpec)
+					if ts.Name.Name != typeName {
+						continue
+					}
+					st, ok := ts.Type.(*ast.StructType)
+					if !ok {
+						return nil, fmt.Errorf("%s is not a struct", typeName)
+					}
+					return structFields(st)
+				}
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("type %s not found in %s", typeName, dir)
+}
+
+func structFields(st *ast.StructType) ([]modelField, error) {
+	var fields []modelField
+	for _, f := range st.Fields.List {
+		if len(f.Names) == 0 {
+			continue
+		}
+
+		var tag reflect.StructTag
+		if f.Tag != nil {
+			value, err := strconv.Unquote(f.Tag.Value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid tag %s: %w", f.Tag.Value, err)
+			}
+			tag = reflect.StructTag(value)
+		}
+
+		for _, name := range f.Names {
+			if !name.IsExported() {
+				continue
+			}
+			field, skip, err := parseField(name.Name, f.Type, tag)
+			if err != nil {
+				return nil, err
+			}
+			if !skip {
+				fields = append(fields, field)
+			}
+		}
+	}
+	return fields, nil
+}
+
+func parseField(name string, expr ast.Expr, tag reflect.StructTag) (modelField, bool, error) {
+	options := tag.Get("olympian")
+	if options == "-" {
+		return modelField{}, true, nil
+	}
+
+	field := modelField{column: snakeCase(name)}
+	if column := strings.Split(tag.Get("db"), ",")[0]; column != "" && column != "-" {
+		field.column = column
+	}
+	if name == "ID" {
+		field.primary = true
+	}
+
+	var explicitType string
+	for _, option := range splitOptions(options) {
+		option = strings.TrimSpace(option)
+		if option == "" {
+			continue
+		}
+		key, value, _ := strings.Cut(option, ":")
+		switch key {
+		case "column":
+			field.column = value
+		case "type":
+			explicitType = value
+		case "nullable":
+			field.nullable = true
+		case "unique":
+			field.unique = true
+		case "primary":
+			field.primary = true
+		case "default":
+			field.dflt = value
+		case "fk":
+			table, column, ok := strings.Cut(value, ".")
+			if !ok {
+				return field, false, fmt.Errorf("field %s: fk must be written as table.column, got %q", name, value)
+			}
+			field.refTable, field.refColumn = table, column
+		case "ondelete":
+			field.onDelete = value
+		default:
+			return field, false, fmt.Errorf("field %s: unknown olympian tag option %q", name, key)
+		}
+	}
+
+	goType, nullable := goTypeName(expr)
+	field.nullable = field.nullable || nullable
+
+	if explicitType != "" {
+		if err := field.setType(explicitType); err != nil {
+			return field, false, fmt.Errorf("field %s: %w", name, err)
+		}
+		return field, false, nil
+	}
+
+	if err := field.setGoType(goType); err != nil {
+		return field, false, fmt.Errorf("field %s: %w (add an olympian:\"type:...\" tag)", name, err)
+	}
+	return field, false, nil
+}
+
+// splitOptions splits a tag on commas that are not inside parentheses, so
+// type:decimal(10,2) stays in one piece.
+func splitOptions(tag string) []string {
+	var options []string
+	depth, start := 0, 0
+	for i, r := range tag {
+		switch r {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				options = append(options, tag[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(options, tag[start:])
+}
+
+func (f *modelField) setType(t string) error {
+	switch strings.ToLower(t) {
+	case "uuid":
+		f.builder = "Uuid"
+	case "string":
+		f.builder = "String"
+	case "text":
+		f.builder = "Text"
+	case "integer", "int":
+		f.builder = "Integer"
+	case "bigint", "biginteger":
+		f.builder = "BigInteger"
+	case "boolean", "bool":
+		f.builder = "Boolean"
+	case "timestamp":
+		f.builder = "Timestamp"
+	case "date":
+		f.builder = "Date"
+	case "json":
+		f.builder = "Json"
+	case "jsonb":
+		f.builder = "Jsonb"
+	case "decimal":
+		f.builder, f.args = "Decimal", []string{"10", "2"}
+	case "smallint", "smallinteger":
+		f.builder = "SmallInteger"
+	case "tinyint", "tinyinteger":
+		f.builder = "TinyInteger"
+	case "float":
+		f.builder = "Float"
+	case "double":
+		f.builder = "Double"
+	case "mediumtext":
+		f.builder = "MediumText"
+	case "longtext":
+		f.builder = "LongText"
+	case "binary":
+		f.builder = "Binary"
+	case "blob":
+		f.builder = "Blob"
+	case "time":
+		f.builder = "Time"
+	case "datetime":
+		f.builder = "DateTime"
+	case "year":
+		f.builder = "Year"
+	case "ipaddress":
+		f.builder = "IpAddress"
+	case "macaddress":
+		f.builder = "MacAddress"
+	default:
+		if m := lengthTagPattern.FindStringSubmatch(strings.ToLower(t)); m != nil {
+			builders := map[string]string{"string": "String", "char": "Char", "binary": "Binary"}
+			f.builder, f.args = builders[m[1]], []string{m[2]}
+			return nil
+		}
+		m := decimalTagPattern.FindStringSubmatch(strings.ToLower(t))
+		if m == nil {
+			return fmt.Errorf("unknown column type %q", t)
+		}
+		f.builder, f.args = "Decimal", []string{m[1], m[2]}
+	}
+	return nil
+}
+
+func (f *modelField) setGoType(goType string) error {
+	switch goType {
+	case "string":
+		return f.setType("string")
+	case "int", "int8", "int16", "int32", "uint", "uint8", "uint16", "uint32":
+		return f.setType("integer")
+	case "int64", "uint64":
+		return f.setType("bigint")
+	case "bool":
+		return f.setType("boolean")
+	case "float32":
+		return f.setType("float")
+	case "float64":
+		return f.setType("double")
+	case "time.Time":
+		return f.setType("timestamp")
+	case "uuid.UUID":
+		return f.setType("uuid")
+	case "json.RawMessage", "map", "slice", "struct":
+		return f.setType("json")
+	case "[]byte":
+		return f.setType("blob")
+	case "sql.NullString":
+		f.nullable = true
+		return f.setType("string")
+	case "sql.NullInt16", "sql.NullInt32", "sql.NullByte":
+		f.nullable = true
+		return f.setType("integer")
+	case "sql.NullInt64":
+		f.nullable = true
+		return f.setType("bigint")
+	case "sql.NullBool":
+		f.nullable = true
+		return f.setType("boolean")
+	case "sql.NullFloat64":
+		f.nullable = true
+		return f.setType("double")
+	case "sql.NullTime":
+		f.nullable = true
+		return f.setType("timestamp")
+	}
+	return fmt.Errorf("cannot infer column type for Go type %s", goType)
+}
+
+// goTypeName reduces a field type to the name used by setGoType, reporting
+// pointers as nullable.
+func goTypeName(expr ast.Expr) (string, bool) {
+	switch t := expr.(type) {
+	case *ast.StarExpr:
+		name, _ := goTypeName(t.X)
+		return name, true
+	case *ast.Ident:
+		return t.Name, false
+	case *ast.SelectorExpr:
+		if pkg, ok := t.X.(*ast.Ident); ok {
+			return pkg.Name + "." + t.Sel.Name, false
+		}
+	case *ast.ArrayType:
+		if elt, ok := t.Elt.(*ast.Ident); ok && elt.Name == "byte" {
+			return "[]byte", false
+		}
+		return "slice", false
+	case *ast.MapType:
+		return "map", false
+	case *ast.StructType:
+		return "struct", false
+	}
+	return fmt.Sprintf("%T", expr), false
+}
+
+func (f modelField) call() string {
+	args := append([]string{strconv.Quote(f.column)}, f.args...)
+	call := fmt.Sprintf("olympian.%s(%s)", f.builder, strings.Join(args, ", "))
+	if f.primary {
+		call += ".Primary()"
+	}
+	if f.nullable {
+		call += ".Nullable()"
+	}
+	if f.unique {
+		call += ".Unique()"
+	}
+	if f.dflt != "" {
+		call += fmt.Sprintf(".Default(%s)", defaultArg(f.builder, f.dflt))
+	}
+	return call
+}
+
+func (f modelField) foreignCall() string {
+	call := fmt.Sprintf("olympian.Foreign(%q).References(%q).On(%q)", f.column, f.refColumn, f.refTable)
+	if f.onDelete != "" {
+		call += fmt.Sprintf(".OnDelete(%q)", f.onDelete)
+	}
+	return call
+}
+
+func defaultArg(builder, value string) string {
+	switch builder {
+	case "Integer", "BigInteger", "SmallInteger", "TinyInteger", "Decimal", "Float", "Double", "Year":
+		if _, err := strconv.ParseFloat(value, 64); err == nil {
+			return value
+		}
+	case "Boolean":
+		if b, err := strconv.ParseBool(value); err == nil {
+			return strconv.FormatBool(b)
+		}
+	}
+	return strconv.Quote(value)
+}
+
+var columnBuilders = map[string]bool{
+	"Uuid": true, "String": true, "Text": true, "Integer": true, "BigInteger": true,
+	"Boolean": true, "Decimal": true, "Timestamp": true, "Date": true, "Json": true,
+	"Jsonb": true, "SmallInteger": true, "TinyInteger": true, "Float": true, "Double": true,
+	"Char": true, "MediumText": true, "LongText": true, "Binary": true, "Blob": true,
+	"Time": true, "DateTime": true, "Year": true, "IpAddress": true, "MacAddress": true,
+	"Enum": true,
+}
+
+// existingColumns scans the Up functions of the migrations in dir and
+// returns the columns each table ends up with.
+func existingColumns(dir string) (map[string]map[string]bool, error) {
+	tables := make(map[string]map[string]bool)
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+
+	fset := token.NewFileSet()
+	for _, path := range files {
+		file, err := parser.ParseFile(fset, path, nil, 0)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+
+		ast.Inspect(file, func(n ast.Node) bool {
+			kv, ok := n.(*ast.KeyValueExpr)
+			if !ok {
+				return true
+			}
+			if key, ok := kv.Key.(*ast.Ident); !ok || key.Name != "Up" {
+				return true
+			}
+			ast.Inspect(kv.Value, func(n ast.Node) bool {
+				call, ok := n.(*ast.CallExpr)
+				if !ok {
+					return true
+				}
+				applyTableCall(tables, call)
+				return true
+			})
+			return false
+		})
+	}
+
+	return tables, nil
+}
+
+// applyTableCall records olympian.Table("x").Create/CreateWith/Modify/ModifyWith/
+// Drop/DropColumn.
+func applyTableCall(tables map[string]map[string]bool, call *ast.CallExpr) {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return
+	}
+	inner, ok := sel.X.(*ast.CallExpr)
+	if !ok {
+		return
+	}
+	innerSel, ok := inner.Fun.(*ast.SelectorExpr)
+	if !ok || innerSel.Sel.Name != "Table" || len(inner.Args) == 0 {
+		return
+	}
+	table, ok := stringLiteral(inner.Args[0])
+	if !ok {
+		return
+	}
+
+	switch sel.Sel.Name {
+	case "Create", "CreateWith", "Modify", "ModifyWith":
+		if tables[table] == nil {
+			tables[table] = make(map[string]bool)
+		}
+		if len(call.Args) == 0 {
+			return
+		}
+		ast.Inspect(call.Args[0], func(n ast.Node) bool {
+			c, ok := n.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+			if s, ok := c.Fun.(*ast.SelectorExpr); ok && columnBuilders[s.Sel.Name] && len(c.Args) > 0 {
+				if column, ok := stringLiteral(c.Args[0]); ok {
+					tables[table][column] = true
+				}
+			}
+			if s, ok := c.Fun.(*ast.SelectorExpr); ok && s.Sel.Name == "Timestamps" {
+				tables[table]["created_at"] = true
+				tables[table]["updated_at"] = true
+			}
+			if s, ok := c.Fun.(*ast.SelectorExpr); ok && s.Sel.Name == "SoftDeletes" {
+				tables[table]["deleted_at"] = true
+			}
+			return true
+		})
+	case "Drop":
+		delete(tables, table)
+	case "DropColumn":
+		if len(call.Args) > 0 {
+			if column, ok := stringLiteral(call.Args[0]); ok && tables[table] != nil {
+				delete(tables[table], column)
+			}
+		}
+	}
+}
+
+func stringLiteral(expr ast.Expr) (string, bool) {
+	lit, ok := expr.(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return "", false
+	}
+	value, err := strconv.Unquote(lit.Value)
+	return value, err == nil
+}
+
+func modelMigrationSource(name, table string, fields []modelField, create bool) ([]byte, error) {
+	var primary []string
+	for _, f := range fields {
+		if f.primary {
+			primary = append(primary, strconv.Quote(f.column))
+		}
+	}
+	composite := create && len(primary) > 1
+
+	var body, foreign []string
+	for _, f := range fields {
+		if composite {
+			f.primary = false
+		}
+		body = append(body, f.call())
+		if f.refTable != "" {
+			foreign = append(foreign, f.foreignCall())
+		}
+	}
+	if composite {
+		body = append(body, fmt.Sprintf("olympian.PrimaryKey(%s)", strings.Join(primary, ", ")))
+	}
+	body = append(body, foreign...)
+
+	var up, down string
+	if create {
+		up = fmt.Sprintf("return olympian.Table(%q).Create(func() {\n%s\n})", table, strings.Join(body, "\n"))
+		down = fmt.Sprintf("return olympian.Table(%q).Drop()", table)
+	} else {
+		up = fmt.Sprintf("return olympian.Table(%q).Modify(func() {\n%s\n})", table, strings.Join(body, "\n"))
+		var drops []string
+		// The foreign keys go first, as most databases refuse to drop a
+		// column a constraint still uses.
+		for i := len(fields) - 1; i >= 0; i-- {
+			if fields[i].refTable != "" {
+				drops = append(drops, fmt.Sprintf("if err := olympian.Table(%q).DropForeign(%q); err != nil {\nreturn err\n}", table, foreignKeyName(table, fields[i].column)))
+			}
+		}
+		for i := len(fields) - 1; i >= 0; i-- {
+			drops = append(drops, fmt.Sprintf("if err := olympian.Table(%q).DropColumn(%q); err != nil {\nreturn err\n}", table, fields[i].column))
+		}
+		down = strings.Join(drops, "\n") + "\nreturn nil"
+	}
+
+	source := fmt.Sprintf(`package migrations
+
+import (
+	"github.com/ichtrojan/olympian"
+)
+
+func init() {
+	olympian.RegisterMigration(olympian.Migration{
+		Name: %q,
+		Up: func() error {
+			%s
+		},
+		Down: func() error {
+			%s
+		},
+	})
+}
+`, name, up, down)
+
+	return format.Source([]byte(source))
+}
+
+// foreignKeyName matches the name olympian gives constraints declared
+// without Name.
+func foreignKeyName(table, column string) string {
+	if i := strings.LastIndex(table, "."); i >= 0 {
+		table = table[i+1:]
+	}
+	return fmt.Sprintf("fk_%s_%s", table, column)
+}
+
+func snakeCase(name string) string {
+	runes := []rune(name)
+	var b strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			prevLower := i > 0 && !unicode.IsUpper(runes[i-1])
+			nextLower := i > 0 && i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if prevLower || nextLower {
+				b.WriteByte('_')
+			}
+			b.WriteRune(unicode.ToLower(r))
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func pluralize(name string) string {
+	switch {
+	case strings.HasSuffix(name, "y") && len(name) > 1 && !strings.ContainsRune("aeiou", rune(name[len(name)-2])):
+		return name[:len(name)-1] + "ies"
+	case strings.HasSuffix(name, "s"), strings.HasSuffix(name, "x"),
+		strings.HasSuffix(name, "ch"), strings.HasSuffix(name, "sh"):
+		return name + "es"
+	}
+	return name + "s"
+}
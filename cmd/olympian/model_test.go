@@ -0,0 +1,289 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testModel = `package models
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type User struct {
+	ID         uuid.UUID
+	BusinessID uuid.UUID ` + "`olympian:\"fk:businesses.id,ondelete:cascade\"`" + `
+	Email      string    ` + "`olympian:\"unique\"`" + `
+	Bio        string    ` + "`olympian:\"type:text\"`" + `
+	Balance    float64   ` + "`olympian:\"type:decimal(12,4),default:0\"`" + `
+	Nickname   *string
+	Summary    *string ` + "`olympian:\"type:text\"`" + `
+	Score      float64
+	Avatar     []byte
+	Phone      sql.NullString
+	Status     string ` + "`db:\"state\" olympian:\"default:active\"`" + `
+	CreatedAt  time.Time
+	Password   string ` + "`olympian:\"-\"`" + `
+	internal   string
+}
+`
+
+func writeModel(t *testing.T) string {
+	dir := filepath.Join(t.TempDir(), "models")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatalf("Failed to create models directory: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "user.go"), []byte(testModel), 0644); err != nil {
+		t.Fatalf("Failed to write model: %v", err)
+	}
+	return dir
+}
+
+func TestParseModel(t *testing.T) {
+	fields, err := parseModel(writeModel(t), "User")
+	if err != nil {
+		t.Fatalf("Failed to parse model: %v", err)
+	}
+
+	var calls []string
+	for _, f := range fields {
+		calls = append(calls, f.call())
+	}
+
+	expected := []string{
+		`olympian.Uuid("id").Primary()`,
+		`olympian.Uuid("business_id")`,
+		`olympian.String("email").Unique()`,
+		`olympian.Text("bio")`,
+		`olympian.Decimal("balance", 12, 4).Default(0)`,
+		`olympian.String("nickname").Nullable()`,
+		`olympian.Text("summary").Nullable()`,
+		`olympian.Double("score")`,
+		`olympian.Blob("avatar")`,
+		`olympian.String("phone").Nullable()`,
+		`olympian.String("state").Default("active")`,
+		`olympian.Timestamp("created_at")`,
+	}
+
+	if len(calls) != len(expected) {
+		t.Fatalf("Expected %d columns, got %d: %v", len(expected), len(calls), calls)
+	}
+	for i := range expected {
+		if calls[i] != expected[i] {
+			t.Errorf("Expected %s, got %s", expected[i], calls[i])
+		}
+	}
+
+	if fk := fields[1].foreignCall(); fk != `olympian.Foreign("business_id").References("id").On("businesses").OnDelete("cascade")` {
+		t.Errorf("Unexpected foreign key call %s", fk)
+	}
+}
+
+func TestSetTypeTags(t *testing.T) {
+	tests := map[string]string{
+		"string(100)": `olympian.String("c", 100)`,
+		"char(2)":     `olympian.Char("c", 2)`,
+		"tinyint":     `olympian.TinyInteger("c")`,
+		"double":      `olympian.Double("c")`,
+		"datetime":    `olympian.DateTime("c")`,
+		"ipaddress":   `olympian.IpAddress("c")`,
+		"jsonb":       `olympian.Jsonb("c")`,
+	}
+	for tag, expected := range tests {
+		f := modelField{column: "c"}
+		if err := f.setType(tag); err != nil {
+			t.Errorf("setType(%s) failed: %v", tag, err)
+			continue
+		}
+		if call := f.call(); call != expected {
+			t.Errorf("setType(%s) produced %s, expected %s", tag, call, expected)
+		}
+	}
+}
+
+func TestParseModelUnknownType(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "models")
+	_ = os.MkdirAll(dir, 0755)
+	source := "package models\n\ntype Event struct {\n\tPayload chan int\n}\n"
+	if err := os.WriteFile(filepath.Join(dir, "event.go"), []byte(source), 0644); err != nil {
+		t.Fatalf("Failed to write model: %v", err)
+	}
+
+	if _, err := parseModel(dir, "Event"); err == nil || !strings.Contains(err.Error(), "Payload") {
+		t.Errorf("Expected an error naming the Payload field, got %v", err)
+	}
+}
+
+func TestResolveModel(t *testing.T) {
+	root := t.TempDir()
+	for _, dir := range []string{"apple/models", "models"} {
+		if err := os.MkdirAll(filepath.Join(root, dir), 0755); err != nil {
+			t.Fatalf("Failed to create %s: %v", dir, err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(root, "go.mod"), []byte("module app\n\ngo 1.21\n"), 0644); err != nil {
+		t.Fatalf("Failed to write go.mod: %v", err)
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Failed to get working directory: %v", err)
+	}
+	if err := os.Chdir(root); err != nil {
+		t.Fatalf("Failed to change directory: %v", err)
+	}
+	defer func() { _ = os.Chdir(wd) }()
+
+	tests := map[string]string{
+		"app/models.User":   "models",
+		"models.User":       "models",
+		"apple/models.User": filepath.FromSlash("apple/models"),
+		"app.User":          ".",
+	}
+	for ref, expected := range tests {
+		dir, typeName, err := resolveModel(ref)
+		if err != nil {
+			t.Errorf("resolveModel(%s) failed: %v", ref, err)
+			continue
+		}
+		if dir != expected || typeName != "User" {
+			t.Errorf("resolveModel(%s) = %s, %s, expected %s, User", ref, dir, typeName, expected)
+		}
+	}
+
+	if _, _, err := resolveModel("app/missing.User"); err == nil {
+		t.Error("Expected an error for a missing package directory")
+	}
+}
+
+func TestExistingColumns(t *testing.T) {
+	dir := t.TempDir()
+	migrations := map[string]string{
+		"1_create_users_table.go": `package migrations
+
+import "github.com/ichtrojan/olympian"
+
+func init() {
+	olympian.RegisterMigration(olympian.Migration{
+		Name: "1_create_users_table",
+		Up: func() error {
+			return olympian.Table("users").Create(func() {
+				olympian.Uuid("id").Primary()
+				olympian.String("email")
+				olympian.String("legacy")
+				olympian.Timestamps()
+			})
+		},
+		Down: func() error {
+			return olympian.Table("users").Modify(func() {
+				olympian.String("ignored")
+			})
+		},
+	})
+}
+`,
+		"2_update_users_table.go": `package migrations
+
+import "github.com/ichtrojan/olympian"
+
+func init() {
+	olympian.RegisterMigration(olympian.Migration{
+		Name: "2_update_users_table",
+		Up: func() error {
+			if err := olympian.Table("users").ModifyWith(func(t *olympian.Blueprint) {
+				t.Text("bio")
+			}); err != nil {
+				return err
+			}
+			return olympian.Table("users").DropColumn("legacy")
+		},
+		Down: func() error { return nil },
+	})
+}
+`,
+	}
+	for name, source := range migrations {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(source), 0644); err != nil {
+			t.Fatalf("Failed to write migration: %v", err)
+		}
+	}
+
+	tables, err := existingColumns(dir)
+	if err != nil {
+		t.Fatalf("Failed to scan migrations: %v", err)
+	}
+
+	users := tables["users"]
+	for _, column := range []string{"id", "email", "bio", "created_at", "updated_at"} {
+		if !users[column] {
+			t.Errorf("Expected users.%s to exist", column)
+		}
+	}
+	for _, column := range []string{"legacy", "ignored"} {
+		if users[column] {
+			t.Errorf("Expected users.%s not to exist", column)
+		}
+	}
+}
+
+func TestModelMigrationSource(t *testing.T) {
+	fields, err := parseModel(writeModel(t), "User")
+	if err != nil {
+		t.Fatalf("Failed to parse model: %v", err)
+	}
+
+	source, err := modelMigrationSource("1_create_users_table", "users", fields, true)
+	if err != nil {
+		t.Fatalf("Failed to generate migration: %v", err)
+	}
+	if !strings.Contains(string(source), `olympian.Foreign("business_id")`) {
+		t.Errorf("Expected a foreign key in:\n%s", source)
+	}
+
+	source, err = modelMigrationSource("2_add_bio_to_users_table", "users", fields[3:4], false)
+	if err != nil {
+		t.Fatalf("Failed to generate migration: %v", err)
+	}
+	code := string(source)
+	if !strings.Contains(code, `olympian.Table("users").Modify(func() {`) || !strings.Contains(code, `DropColumn("bio")`) {
+		t.Errorf("Unexpected modify migration:\n%s", code)
+	}
+
+	source, err = modelMigrationSource("3_add_business_id_to_users_table", "users", fields[1:2], false)
+	if err != nil {
+		t.Fatalf("Failed to generate migration: %v", err)
+	}
+	code = string(source)
+	dropForeign := strings.Index(code, `olympian.Table("users").DropForeign("fk_users_business_id")`)
+	dropColumn := strings.Index(code, `olympian.Table("users").DropColumn("business_id")`)
+	if dropForeign < 0 || dropColumn < dropForeign {
+		t.Errorf("Expected the foreign key to be dropped before its column:\n%s", code)
+	}
+}
+
+func TestSnakeCaseAndPluralize(t *testing.T) {
+	tests := map[string]string{
+		"ID":         "id",
+		"UserID":     "user_id",
+		"CreatedAt":  "created_at",
+		"HTTPServer": "http_server",
+	}
+	for in, expected := range tests {
+		if result := snakeCase(in); result != expected {
+			t.Errorf("snakeCase(%s) = %s, expected %s", in, result, expected)
+		}
+	}
+
+	plurals := map[string]string{"user": "users", "category": "categories", "address": "addresses", "day": "days"}
+	for in, expected := range plurals {
+		if result := pluralize(in); result != expected {
+			t.Errorf("pluralize(%s) = %s, expected %s", in, result, expected)
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var diffWriteName string
+
+func init() {
+	schemaCmd.PersistentFlags().StringVar(&migrationPath, "path", "./migrations", "Path to migrations directory")
+	schemaDiffCmd.Flags().StringVar(&diffWriteName, "write", "", "Write the difference as a new migration with this name")
+
+	schemaCmd.AddCommand(schemaDiffCmd)
+
+	rootCmd.AddCommand(schemaCmd)
+}
+
+var schemaCmd = &cobra.Command{
+	Use:   "schema",
+	Short: "Inspect the database schema",
+}
+
+var schemaDiffCmd = &cobra.Command{
+	Use:   "diff",
+	Short: "Compare the live database with the schema produced by the migrations",
+	Long: `Replays all migrations into a scratch database, introspects both it and the
+live database, and reports added, removed and changed tables, columns, indexes
+and foreign keys.
+
+SQLite uses a temporary file as the scratch database. For PostgreSQL and MySQL
+set DB_SCRATCH_DSN to an empty database that may be dropped and recreated.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if diffWriteName == "" {
+			return runWithCmdMigrate("diff")
+		}
+		return runWithCmdMigrate("diff", migrationPath, diffWriteName)
+	},
+}
@@ -0,0 +1,35 @@
+package olympian
+
+import "fmt"
+
+// Comment sets the comment of the table, stored with Create and replaced
+// with Modify. SQLite has no comments and ignores it.
+func (tb *TableBuilder) Comment(text string) *TableBuilder {
+	tb.comment = text
+	return tb
+}
+
+// Comment documents the column in the database. SQLite has no comments and
+// ignores it.
+func (cb *ColumnBuilder) Comment(text string) *ColumnBuilder {
+	cb.column.comment = text
+	return cb
+}
+
+// commentStatements returns the COMMENT ON statements for the table and
+// column comments of tb, which PostgreSQL cannot set inline.
+func (d *PostgresDialect) commentStatements(tb *TableBuilder) []string {
+	table := d.QuoteIdentifier(tb.tableName)
+
+	var sqls []string
+	if tb.comment != "" {
+		sqls = append(sqls, fmt.Sprintf("COMMENT ON TABLE %s IS %s", table, quoteLiteral(d, tb.comment)))
+	}
+	for _, col := range tb.columns {
+		if col.comment != "" {
+			sqls = append(sqls, fmt.Sprintf("COMMENT ON COLUMN %s.%s IS %s",
+				table, d.QuoteIdentifier(col.name), quoteLiteral(d, col.comment)))
+		}
+	}
+	return sqls
+}
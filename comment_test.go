@@ -0,0 +1,95 @@
+package olympian
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCommentSQL(t *testing.T) {
+	tb := &TableBuilder{
+		tableName: "users",
+		comment:   "Registered users",
+		columns: []*Column{
+			{name: "id", dataType: "integer", primary: true},
+			{name: "email", dataType: "string", comment: "Login e-mail, it's unique"},
+		},
+	}
+
+	expected := `CREATE TABLE IF NOT EXISTS users (
+  id INTEGER PRIMARY KEY NOT NULL,
+  email VARCHAR(255) NOT NULL
+);;
+COMMENT ON TABLE users IS 'Registered users';
+COMMENT ON COLUMN users.email IS 'Login e-mail, it''s unique'`
+	if sql := buildCreateTable(t, &PostgresDialect{}, tb); sql != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, sql)
+	}
+
+	mysql := buildCreateTable(t, &MySQLDialect{}, tb)
+	for _, fragment := range []string{
+		"email VARCHAR(255) NOT NULL COMMENT 'Login e-mail, it''s unique'",
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='Registered users';",
+	} {
+		if !strings.Contains(mysql, fragment) {
+			t.Errorf("MySQL SQL should contain %s, got:\n%s", fragment, mysql)
+		}
+	}
+
+	if sql := buildCreateTable(t, &SQLiteDialect{}, tb); strings.Contains(sql, "COMMENT") {
+		t.Errorf("SQLite SQL should not contain comments, got:\n%s", sql)
+	}
+}
+
+func TestModifyCommentSQL(t *testing.T) {
+	tb := &TableBuilder{
+		tableName: "users",
+		comment:   "Active users",
+		columns: []*Column{
+			{name: "name", dataType: "string", change: true, existing: &ColumnInfo{Name: "name", Comment: "Display name"}},
+		},
+	}
+
+	sqls, err := (&MySQLDialect{}).BuildModifyTable(tb)
+	if err != nil {
+		t.Fatalf("Failed to build SQL: %v", err)
+	}
+	expected := "ALTER TABLE users MODIFY COLUMN name VARCHAR(255) NOT NULL COMMENT 'Display name', COMMENT='Active users'"
+	if len(sqls) != 1 || sqls[0] != expected {
+		t.Errorf("Expected %s, got %v", expected, sqls)
+	}
+
+	tb.columns = []*Column{{name: "nickname", dataType: "string", nullable: true, comment: "Shown in mentions"}}
+	sqls, err = (&PostgresDialect{}).BuildModifyTable(tb)
+	if err != nil {
+		t.Fatalf("Failed to build SQL: %v", err)
+	}
+	expectedSQL := []string{
+		"ALTER TABLE users ADD COLUMN nickname VARCHAR(255)",
+		"COMMENT ON TABLE users IS 'Active users'",
+		"COMMENT ON COLUMN users.nickname IS 'Shown in mentions'",
+	}
+	if strings.Join(sqls, "\n") != strings.Join(expectedSQL, "\n") {
+		t.Errorf("Expected %v, got %v", expectedSQL, sqls)
+	}
+
+	if call := columnCall(ColumnInfo{Name: "nickname", Type: "varchar(255)", Comment: "Shown in mentions"}); call != `olympian.String("nickname").Comment("Shown in mentions")` {
+		t.Errorf("Unexpected column call %s", call)
+	}
+}
+
+func TestCommentsSQLite(t *testing.T) {
+	db := setupTestDB(t)
+	defer func() { _ = db.Close() }()
+
+	SetDB(db, &SQLiteDialect{})
+
+	if err := Table("users").Comment("Registered users").Create(func() {
+		Integer("id").Primary()
+		String("email").Comment("Login e-mail")
+	}); err != nil {
+		t.Fatalf("Failed to create table with comments: %v", err)
+	}
+	if ok, _ := HasColumn("users", "email"); !ok {
+		t.Error("Expected users.email to exist")
+	}
+}
@@ -0,0 +1,126 @@
+package olympian
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// rawDefault is a default expression written into the DDL as is.
+type rawDefault string
+
+// DefaultRaw sets a default expression such as gen_random_uuid(). It is not
+// quoted or escaped.
+func (cb *ColumnBuilder) DefaultRaw(expression string) *ColumnBuilder {
+	cb.column.defaultValue = rawDefault(expression)
+	return cb
+}
+
+// UseCurrent defaults the column to CURRENT_TIMESTAMP.
+func (cb *ColumnBuilder) UseCurrent() *ColumnBuilder {
+	return cb.DefaultRaw("CURRENT_TIMESTAMP")
+}
+
+// decimalLiteral matches the plain decimal numbers that can be written into
+// the DDL unquoted. strconv.ParseFloat also accepts NaN, Inf and hex floats.
+var decimalLiteral = regexp.MustCompile(`^[+-]?(\d+(\.\d*)?|\.\d+)([eE][+-]?\d+)?$`)
+
+// columnDefault renders the default of col as a literal for the dialect.
+func columnDefault(dialect Dialect, col *Column) string {
+	_, isMySQL := dialect.(*MySQLDialect)
+
+	var literal string
+	switch value := col.defaultValue.(type) {
+	case rawDefault:
+		return string(value)
+	case bool:
+		return booleanLiteral(dialect, value)
+	case string:
+		// Numbers and booleans given as strings keep working on numeric and
+		// boolean columns.
+		if numericColumn(col.dataType) {
+			if decimalLiteral.MatchString(value) {
+				return value
+			}
+		}
+		if col.dataType == "boolean" {
+			if b, err := strconv.ParseBool(value); err == nil {
+				return booleanLiteral(dialect, b)
+			}
+		}
+		literal = quoteLiteral(dialect, value)
+	case time.Time:
+		layout := "2006-01-02 15:04:05"
+		switch col.dataType {
+		case "date":
+			layout = "2006-01-02"
+		case "time":
+			layout = "15:04:05"
+		}
+		literal = quoteLiteral(dialect, value.Format(layout))
+	case json.RawMessage:
+		literal = quoteLiteral(dialect, string(value))
+	default:
+		v := reflect.ValueOf(value)
+		switch v.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+			reflect.Float32, reflect.Float64:
+			return fmt.Sprint(value)
+		case reflect.Map, reflect.Slice, reflect.Array, reflect.Struct:
+			encoded, err := json.Marshal(value)
+			if err != nil {
+				encoded = []byte(fmt.Sprint(value))
+			}
+			literal = quoteLiteral(dialect, string(encoded))
+		default:
+			literal = quoteLiteral(dialect, fmt.Sprint(value))
+		}
+	}
+
+	// MySQL only allows literal defaults on TEXT, BLOB and JSON columns when
+	// they are written as expressions.
+	if isMySQL && expressionDefaultOnly(col.dataType) {
+		return "(" + literal + ")"
+	}
+	return literal
+}
+
+func booleanLiteral(dialect Dialect, value bool) string {
+	if _, isMySQL := dialect.(*MySQLDialect); isMySQL {
+		if value {
+			return "1"
+		}
+		return "0"
+	}
+	return strconv.FormatBool(value)
+}
+
+// quoteLiteral quotes a string literal, doubling single quotes. MySQL also
+// treats backslashes as escapes unless NO_BACKSLASH_ESCAPES is set.
+func quoteLiteral(dialect Dialect, value string) string {
+	if _, isMySQL := dialect.(*MySQLDialect); isMySQL {
+		value = strings.ReplaceAll(value, `\`, `\\`)
+	}
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
+
+func numericColumn(dataType string) bool {
+	switch dataType {
+	case "integer", "bigint", "smallint", "tinyint", "float", "double", "year":
+		return true
+	}
+	return strings.HasPrefix(dataType, "decimal")
+}
+
+func expressionDefaultOnly(dataType string) bool {
+	switch dataType {
+	case "text", "mediumtext", "longtext", "json", "jsonb", "blob":
+		return true
+	}
+	return false
+}
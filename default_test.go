@@ -0,0 +1,88 @@
+package olympian
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestColumnDefaultLiterals(t *testing.T) {
+	postgres, mysql, sqlite := &PostgresDialect{}, &MySQLDialect{}, &SQLiteDialect{}
+	at := time.Date(2024, 3, 1, 9, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		dialect  Dialect
+		column   *Column
+		expected string
+	}{
+		{postgres, &Column{dataType: "string", defaultValue: "O'Reilly"}, "'O''Reilly'"},
+		{postgres, &Column{dataType: "string", defaultValue: "x'); DROP TABLE users; --"}, "'x''); DROP TABLE users; --'"},
+		{mysql, &Column{dataType: "string", defaultValue: `a\'; DROP TABLE users; --`}, `'a\\''; DROP TABLE users; --'`},
+		{postgres, &Column{dataType: "boolean", defaultValue: true}, "true"},
+		{mysql, &Column{dataType: "boolean", defaultValue: true}, "1"},
+		{mysql, &Column{dataType: "boolean", defaultValue: "false"}, "0"},
+		{sqlite, &Column{dataType: "boolean", defaultValue: false}, "false"},
+		{postgres, &Column{dataType: "integer", defaultValue: 42}, "42"},
+		{postgres, &Column{dataType: "integer", defaultValue: "7"}, "7"},
+		{postgres, &Column{dataType: "string", defaultValue: "7"}, "'7'"},
+		{postgres, &Column{dataType: "decimal(8,2)", defaultValue: "-1.25e3"}, "-1.25e3"},
+		{postgres, &Column{dataType: "double", defaultValue: "NaN"}, "'NaN'"},
+		{postgres, &Column{dataType: "double", defaultValue: "Inf"}, "'Inf'"},
+		{mysql, &Column{dataType: "float", defaultValue: "Infinity"}, "'Infinity'"},
+		{postgres, &Column{dataType: "double", defaultValue: "0x1p-2"}, "'0x1p-2'"},
+		{postgres, &Column{dataType: "integer", defaultValue: "1; DROP TABLE users"}, "'1; DROP TABLE users'"},
+		{sqlite, &Column{dataType: "double", defaultValue: 1.5}, "1.5"},
+		{postgres, &Column{dataType: "timestamp", defaultValue: at}, "'2024-03-01 09:30:00'"},
+		{postgres, &Column{dataType: "date", defaultValue: at}, "'2024-03-01'"},
+		{postgres, &Column{dataType: "json", defaultValue: map[string]interface{}{"tags": []string{"it's"}}}, `'{"tags":["it''s"]}'`},
+		{mysql, &Column{dataType: "json", defaultValue: json.RawMessage(`{}`)}, "('{}')"},
+		{postgres, &Column{dataType: "uuid", defaultValue: rawDefault("gen_random_uuid()")}, "gen_random_uuid()"},
+		{mysql, &Column{dataType: "timestamp", defaultValue: rawDefault("CURRENT_TIMESTAMP")}, "CURRENT_TIMESTAMP"},
+	}
+
+	for _, tt := range tests {
+		if result := columnDefault(tt.dialect, tt.column); result != tt.expected {
+			t.Errorf("%T %s default %#v: expected %s, got %s", tt.dialect, tt.column.dataType, tt.column.defaultValue, tt.expected, result)
+		}
+	}
+}
+
+func TestDefaultsSQLite(t *testing.T) {
+	db := setupTestDB(t)
+	defer func() { _ = db.Close() }()
+
+	SetDB(db, &SQLiteDialect{})
+
+	if err := Table("books").Create(func() {
+		Integer("id").Primary()
+		String("publisher").Default("O'Reilly")
+		Boolean("available").Default(true)
+		Timestamp("created_at").UseCurrent()
+		String("code").DefaultRaw("(lower(hex(randomblob(4))))")
+	}); err != nil {
+		t.Fatalf("Failed to create table: %v", err)
+	}
+	if _, err := db.Exec("INSERT INTO books (id) VALUES (1)"); err != nil {
+		t.Fatalf("Failed to insert book: %v", err)
+	}
+
+	var publisher, createdAt, code string
+	var available bool
+	err := db.QueryRow("SELECT publisher, available, created_at, code FROM books").Scan(&publisher, &available, &createdAt, &code)
+	if err != nil {
+		t.Fatalf("Failed to read book: %v", err)
+	}
+	if publisher != "O'Reilly" || !available || createdAt == "" || len(code) != 8 {
+		t.Errorf("Unexpected defaults: %q, %v, %q, %q", publisher, available, createdAt, code)
+	}
+
+	table, err := describeTable(db, &SQLiteDialect{}, "books")
+	if err != nil {
+		t.Fatalf("Failed to describe table: %v", err)
+	}
+	col, _ := table.Column("created_at")
+	if call := columnCall(col); !strings.HasSuffix(call, ".UseCurrent()") {
+		t.Errorf("Expected the diff to restore UseCurrent, got %s", call)
+	}
+}
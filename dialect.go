@@ -6,34 +6,46 @@ import (
 )
 
 type Dialect interface {
-	BuildCreateTable(tb *TableBuilder) string
-	BuildModifyTable(tb *TableBuilder) []string
+	BuildCreateTable(tb *TableBuilder) ([]string, error)
+	BuildModifyTable(tb *TableBuilder) ([]string, error)
 	BuildDropTable(tableName string) string
 	BuildDropColumn(tableName, columnName string) string
 	GetDataType(column *Column) string
+	QuoteIdentifier(name string) string
+
+	GetTables(q Querier) ([]string, error)
+	GetColumns(q Querier, tableName string) ([]ColumnInfo, error)
+	GetIndexes(q Querier, tableName string) ([]IndexInfo, error)
+	GetForeignKeys(q Querier, tableName string) ([]ForeignKeyInfo, error)
+	GetViews(q Querier) ([]ViewInfo, error)
 }
 
-type PostgresDialect struct{}
-type MySQLDialect struct{}
-type SQLiteDialect struct{}
-
-var mysqlReservedKeywords = map[string]bool{
-	"limit": true, "order": true, "group": true, "key": true, "index": true,
-	"type": true, "desc": true, "asc": true, "primary": true, "foreign": true,
-	"references": true, "constraint": true, "table": true, "column": true,
-	"select": true, "from": true, "where": true, "join": true, "on": true,
-	"and": true, "or": true, "not": true, "like": true, "in": true,
-	"between": true, "is": true, "null": true, "default": true, "unique": true,
-	"check": true, "cascade": true, "restrict": true, "set": true,
+// The dialects quote identifiers only when needed unless AlwaysQuote is
+// set.
+type PostgresDialect struct {
+	AlwaysQuote bool
 }
 
-func escapeColumnName(name string, dialect Dialect) string {
-	if _, isMySQLDialect := dialect.(*MySQLDialect); isMySQLDialect {
-		if mysqlReservedKeywords[strings.ToLower(name)] {
-			return fmt.Sprintf("`%s`", name)
-		}
+// Engine, Charset, Collation and RowFormat are the table options of CREATE
+// TABLE when the table does not set its own. Engine defaults to InnoDB and
+// Charset to utf8mb4.
+type MySQLDialect struct {
+	AlwaysQuote bool
+	Engine      string
+	Charset     string
+	Collation   string
+	RowFormat   string
+}
+
+type SQLiteDialect struct {
+	AlwaysQuote bool
+}
+
+func columnLength(col *Column, fallback int) int {
+	if col.length > 0 {
+		return col.length
 	}
-	return name
+	return fallback
 }
 
 func (d *PostgresDialect) GetDataType(col *Column) string {
@@ -41,8 +53,10 @@ func (d *PostgresDialect) GetDataType(col *Column) string {
 	case "uuid":
 		return "UUID"
 	case "string":
-		return "VARCHAR(255)"
-	case "text":
+		return fmt.Sprintf("VARCHAR(%d)", columnLength(col, 255))
+	case "char":
+		return fmt.Sprintf("CHAR(%d)", columnLength(col, 255))
+	case "text", "mediumtext", "longtext":
 		return "TEXT"
 	case "integer":
 		if col.autoIncrement {
@@ -54,14 +68,35 @@ func (d *PostgresDialect) GetDataType(col *Column) string {
 			return "BIGSERIAL"
 		}
 		return "BIGINT"
+	case "smallint", "tinyint":
+		if col.autoIncrement {
+			return "SMALLSERIAL"
+		}
+		return "SMALLINT"
+	case "float":
+		return "REAL"
+	case "double":
+		return "DOUBLE PRECISION"
 	case "boolean":
 		return "BOOLEAN"
-	case "timestamp":
+	case "timestamp", "datetime":
 		return "TIMESTAMP"
 	case "date":
 		return "DATE"
-	case "json":
+	case "time":
+		return "TIME"
+	case "year":
+		return "SMALLINT"
+	case "binary", "blob":
+		return "BYTEA"
+	case "ipaddress":
+		return "INET"
+	case "macaddress":
+		return "MACADDR"
+	case "json", "jsonb":
 		return "JSONB"
+	case "enum":
+		return d.QuoteIdentifier(col.enumType)
 	default:
 		if strings.HasPrefix(col.dataType, "decimal") {
 			return "DECIMAL" + strings.TrimPrefix(col.dataType, "decimal")
@@ -70,13 +105,13 @@ func (d *PostgresDialect) GetDataType(col *Column) string {
 	}
 }
 
-func (d *PostgresDialect) BuildCreateTable(tb *TableBuilder) string {
+func (d *PostgresDialect) BuildCreateTable(tb *TableBuilder) ([]string, error) {
 	var parts []string
-	parts = append(parts, fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (", tb.tableName))
+	parts = append(parts, fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (", d.QuoteIdentifier(tb.tableName)))
 
 	var columnDefs []string
 	for _, col := range tb.columns {
-		def := fmt.Sprintf("  %s %s", col.name, d.GetDataType(col))
+		def := fmt.Sprintf("  %s %s%s%s", d.QuoteIdentifier(col.name), d.GetDataType(col), collationClause(d, col), generatedClause(col))
 
 		if col.primary {
 			def += " PRIMARY KEY"
@@ -88,61 +123,115 @@ func (d *PostgresDialect) BuildCreateTable(tb *TableBuilder) string {
 			def += " UNIQUE"
 		}
 		if col.defaultValue != nil {
-			if col.dataType == "boolean" || col.dataType == "integer" || col.dataType == "bigint" {
-				def += fmt.Sprintf(" DEFAULT %s", *col.defaultValue)
-			} else {
-				def += fmt.Sprintf(" DEFAULT '%s'", *col.defaultValue)
-			}
+			def += fmt.Sprintf(" DEFAULT %s", columnDefault(d, col))
+		}
+		if col.check != "" {
+			def += fmt.Sprintf(" CHECK (%s)", col.check)
 		}
 		columnDefs = append(columnDefs, def)
 	}
 
-	for _, fk := range tb.foreignKeys {
-		fkDef := fmt.Sprintf("  CONSTRAINT fk_%s_%s FOREIGN KEY (%s) REFERENCES %s(%s)",
-			tb.tableName, fk.column, fk.column, fk.refTable, fk.refColumn)
+	if len(tb.primaryKey) > 0 {
+		columnDefs = append(columnDefs, fmt.Sprintf("  PRIMARY KEY (%s)", quoteColumns(d, tb.primaryKey)))
+	}
 
-		if fk.onDelete != "" {
-			fkDef += fmt.Sprintf(" ON DELETE %s", strings.ToUpper(fk.onDelete))
-		}
-		if fk.onUpdate != "" {
-			fkDef += fmt.Sprintf(" ON UPDATE %s", strings.ToUpper(fk.onUpdate))
-		}
-		columnDefs = append(columnDefs, fkDef)
+	for _, fk := range tb.foreignKeys {
+		columnDefs = append(columnDefs, "  "+foreignKeyDefinition(d, tb.tableName, fk, true))
+	}
+	for _, check := range tb.checks {
+		columnDefs = append(columnDefs, "  "+checkDefinition(d, check))
 	}
 
 	parts = append(parts, strings.Join(columnDefs, ",\n"))
 	parts = append(parts, ");")
 
-	return strings.Join(parts, "\n")
+	sqls := append(d.createEnumTypes(tb), strings.Join(parts, "\n"))
+	sqls = append(sqls, d.commentStatements(tb)...)
+	return append(sqls, indexStatements(d, tb)...), nil
 }
 
-func (d *PostgresDialect) BuildModifyTable(tb *TableBuilder) []string {
-	var sqls []string
+func (d *PostgresDialect) BuildModifyTable(tb *TableBuilder) ([]string, error) {
+	sqls := d.createEnumTypes(tb)
 	for _, col := range tb.columns {
-		query := fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s",
-			tb.tableName, col.name, d.GetDataType(col))
+		if col.change {
+			sqls = append(sqls, d.buildChangeColumn(tb.tableName, col)...)
+			continue
+		}
+
+		query := fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s%s%s",
+			d.QuoteIdentifier(tb.tableName), d.QuoteIdentifier(col.name), d.GetDataType(col), collationClause(d, col), generatedClause(col))
 
 		if !col.nullable {
 			query += " NOT NULL"
 		}
 		if col.defaultValue != nil {
-			if col.dataType == "boolean" || col.dataType == "integer" || col.dataType == "bigint" {
-				query += fmt.Sprintf(" DEFAULT %s", *col.defaultValue)
-			} else {
-				query += fmt.Sprintf(" DEFAULT '%s'", *col.defaultValue)
-			}
+			query += fmt.Sprintf(" DEFAULT %s", columnDefault(d, col))
+		}
+		if col.check != "" {
+			query += fmt.Sprintf(" CHECK (%s)", col.check)
 		}
 		sqls = append(sqls, query)
 	}
+	table := d.QuoteIdentifier(tb.tableName)
+	for _, fk := range tb.foreignKeys {
+		sqls = append(sqls, fmt.Sprintf("ALTER TABLE %s ADD %s",
+			table, foreignKeyDefinition(d, tb.tableName, fk, true)))
+	}
+	for _, name := range tb.droppedChecks {
+		sqls = append(sqls, fmt.Sprintf("ALTER TABLE %s DROP CONSTRAINT %s", table, d.QuoteIdentifier(name)))
+	}
+	for _, check := range tb.checks {
+		sqls = append(sqls, fmt.Sprintf("ALTER TABLE %s ADD %s", table, checkDefinition(d, check)))
+	}
+	sqls = append(sqls, d.commentStatements(tb)...)
+	return append(sqls, indexStatements(d, tb)...), nil
+}
+
+// buildChangeColumn alters the type and, when set explicitly, the
+// nullability and default. Anything else is left as it is. Enum columns that
+// already are enums only get their new values added.
+func (d *PostgresDialect) buildChangeColumn(tableName string, col *Column) []string {
+	dataType := d.GetDataType(col)
+	switch dataType {
+	case "SERIAL":
+		dataType = "INTEGER"
+	case "BIGSERIAL":
+		dataType = "BIGINT"
+	}
+
+	name := d.QuoteIdentifier(col.name)
+	var sqls, actions []string
+	switch {
+	case col.dataType == "enum" && col.existing != nil && col.existing.Type == "USER-DEFINED":
+		sqls = d.addEnumValues(col)
+	case col.dataType == "enum":
+		sqls = append(sqls, d.createEnumType(dataType, col.enumValues))
+		actions = append(actions, fmt.Sprintf("ALTER COLUMN %s TYPE %s USING %s::text::%s", name, dataType, name, dataType))
+	default:
+		actions = append(actions, fmt.Sprintf("ALTER COLUMN %s TYPE %s%s USING %s::%s", name, dataType, collationClause(d, col), name, dataType))
+	}
+	if col.nullableSet {
+		if col.nullable {
+			actions = append(actions, fmt.Sprintf("ALTER COLUMN %s DROP NOT NULL", name))
+		} else {
+			actions = append(actions, fmt.Sprintf("ALTER COLUMN %s SET NOT NULL", name))
+		}
+	}
+	if col.defaultValue != nil {
+		actions = append(actions, fmt.Sprintf("ALTER COLUMN %s SET DEFAULT %s", name, columnDefault(d, col)))
+	}
+	if len(actions) > 0 {
+		sqls = append(sqls, fmt.Sprintf("ALTER TABLE %s %s", d.QuoteIdentifier(tableName), strings.Join(actions, ", ")))
+	}
 	return sqls
 }
 
 func (d *PostgresDialect) BuildDropTable(tableName string) string {
-	return fmt.Sprintf("DROP TABLE IF EXISTS %s", tableName)
+	return fmt.Sprintf("DROP TABLE IF EXISTS %s", d.QuoteIdentifier(tableName))
 }
 
 func (d *PostgresDialect) BuildDropColumn(tableName, columnName string) string {
-	return fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s", tableName, columnName)
+	return fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s", d.QuoteIdentifier(tableName), d.QuoteIdentifier(columnName))
 }
 
 func (d *MySQLDialect) GetDataType(col *Column) string {
@@ -150,21 +239,51 @@ func (d *MySQLDialect) GetDataType(col *Column) string {
 	case "uuid":
 		return "CHAR(36)"
 	case "string":
-		return "VARCHAR(255)"
+		return fmt.Sprintf("VARCHAR(%d)", columnLength(col, 255))
+	case "char":
+		return fmt.Sprintf("CHAR(%d)", columnLength(col, 255))
 	case "text":
 		return "TEXT"
+	case "mediumtext":
+		return "MEDIUMTEXT"
+	case "longtext":
+		return "LONGTEXT"
 	case "integer":
 		return "INT"
 	case "bigint":
 		return "BIGINT"
+	case "smallint":
+		return "SMALLINT"
+	case "tinyint":
+		return "TINYINT"
+	case "float":
+		return "FLOAT"
+	case "double":
+		return "DOUBLE"
 	case "boolean":
 		return "TINYINT(1)"
 	case "timestamp":
 		return "TIMESTAMP"
+	case "datetime":
+		return "DATETIME"
 	case "date":
 		return "DATE"
-	case "json":
+	case "time":
+		return "TIME"
+	case "year":
+		return "YEAR"
+	case "binary":
+		return fmt.Sprintf("VARBINARY(%d)", columnLength(col, 255))
+	case "blob":
+		return "BLOB"
+	case "ipaddress":
+		return "VARCHAR(45)"
+	case "macaddress":
+		return "VARCHAR(17)"
+	case "json", "jsonb":
 		return "JSON"
+	case "enum":
+		return fmt.Sprintf("ENUM(%s)", quoteValues(col.enumValues))
 	default:
 		if strings.HasPrefix(col.dataType, "decimal") {
 			return "DECIMAL" + strings.TrimPrefix(col.dataType, "decimal")
@@ -173,13 +292,13 @@ func (d *MySQLDialect) GetDataType(col *Column) string {
 	}
 }
 
-func (d *MySQLDialect) BuildCreateTable(tb *TableBuilder) string {
+func (d *MySQLDialect) BuildCreateTable(tb *TableBuilder) ([]string, error) {
 	var parts []string
-	parts = append(parts, fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (", tb.tableName))
+	parts = append(parts, fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (", d.QuoteIdentifier(tb.tableName)))
 
 	var columnDefs []string
 	for _, col := range tb.columns {
-		def := fmt.Sprintf("  %s %s", escapeColumnName(col.name, d), d.GetDataType(col))
+		def := fmt.Sprintf("  %s %s%s%s", d.QuoteIdentifier(col.name), d.GetDataType(col), collationClause(d, col), generatedClause(col))
 
 		if col.autoIncrement {
 			def += " AUTO_INCREMENT"
@@ -194,81 +313,153 @@ func (d *MySQLDialect) BuildCreateTable(tb *TableBuilder) string {
 			def += " UNIQUE"
 		}
 		if col.defaultValue != nil {
-			if col.dataType == "boolean" || col.dataType == "integer" || col.dataType == "bigint" {
-				def += fmt.Sprintf(" DEFAULT %s", *col.defaultValue)
-			} else {
-				def += fmt.Sprintf(" DEFAULT '%s'", *col.defaultValue)
-			}
+			def += fmt.Sprintf(" DEFAULT %s", columnDefault(d, col))
+		}
+		if col.comment != "" {
+			def += " COMMENT " + quoteLiteral(d, col.comment)
+		}
+		if col.check != "" {
+			def += fmt.Sprintf(" CHECK (%s)", col.check)
 		}
 		columnDefs = append(columnDefs, def)
 	}
 
-	for _, fk := range tb.foreignKeys {
-		fkDef := fmt.Sprintf("  CONSTRAINT fk_%s_%s FOREIGN KEY (%s) REFERENCES %s(%s)",
-			tb.tableName, fk.column, fk.column, fk.refTable, fk.refColumn)
+	if len(tb.primaryKey) > 0 {
+		columnDefs = append(columnDefs, fmt.Sprintf("  PRIMARY KEY (%s)", quoteColumns(d, tb.primaryKey)))
+	}
 
-		if fk.onDelete != "" {
-			fkDef += fmt.Sprintf(" ON DELETE %s", strings.ToUpper(fk.onDelete))
-		}
-		if fk.onUpdate != "" {
-			fkDef += fmt.Sprintf(" ON UPDATE %s", strings.ToUpper(fk.onUpdate))
-		}
-		columnDefs = append(columnDefs, fkDef)
+	for _, fk := range tb.foreignKeys {
+		columnDefs = append(columnDefs, "  "+foreignKeyDefinition(d, tb.tableName, fk, true))
+	}
+	for _, check := range tb.checks {
+		columnDefs = append(columnDefs, "  "+checkDefinition(d, check))
 	}
 
 	parts = append(parts, strings.Join(columnDefs, ",\n"))
-	parts = append(parts, ") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;")
+	parts = append(parts, ") "+d.createTableOptions(tb)+";")
 
-	return strings.Join(parts, "\n")
+	return append([]string{strings.Join(parts, "\n")}, indexStatements(d, tb)...), nil
 }
 
-func (d *MySQLDialect) BuildModifyTable(tb *TableBuilder) []string {
-	var sqls []string
+// foreignKeyDefinition renders a table constraint. SQLite leaves constraints
+// unnamed unless a name was given.
+func foreignKeyDefinition(d Dialect, tableName string, fk *ForeignKey, named bool) string {
+	var def string
+	if named {
+		def = fmt.Sprintf("CONSTRAINT %s ", d.QuoteIdentifier(fk.constraintName(tableName)))
+	}
+	def += fmt.Sprintf("FOREIGN KEY (%s) REFERENCES %s(%s)",
+		quoteColumns(d, fk.columns), d.QuoteIdentifier(fk.refTable), quoteColumns(d, fk.refColumns))
+
+	if fk.onDelete != "" {
+		def += fmt.Sprintf(" ON DELETE %s", strings.ToUpper(fk.onDelete))
+	}
+	if fk.onUpdate != "" {
+		def += fmt.Sprintf(" ON UPDATE %s", strings.ToUpper(fk.onUpdate))
+	}
+	if fk.deferrable {
+		def += " DEFERRABLE INITIALLY DEFERRED"
+	}
+	return def
+}
+
+// BuildModifyTable adds and changes all columns and foreign keys in a single
+// ALTER TABLE so the table is rebuilt at most once.
+func (d *MySQLDialect) BuildModifyTable(tb *TableBuilder) ([]string, error) {
+	options := d.alterTableOptions(tb)
+	if len(tb.columns) == 0 && len(tb.foreignKeys) == 0 && len(tb.checks) == 0 && len(tb.droppedChecks) == 0 && len(options) == 0 {
+		return indexStatements(d, tb), nil
+	}
+
+	var changes []string
 	for _, col := range tb.columns {
-		query := fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s",
-			tb.tableName, escapeColumnName(col.name, d), d.GetDataType(col))
+		change := "ADD COLUMN"
+		if col.change {
+			change = "MODIFY COLUMN"
+		}
+		change += fmt.Sprintf(" %s %s%s%s", d.QuoteIdentifier(col.name), d.GetDataType(col), collationClause(d, col), generatedClause(col))
 
+		if col.autoIncrement || col.existing != nil && col.existing.AutoIncrement {
+			change += " AUTO_INCREMENT"
+		}
 		if !col.nullable {
-			query += " NOT NULL"
+			change += " NOT NULL"
 		}
 		if col.defaultValue != nil {
-			if col.dataType == "boolean" || col.dataType == "integer" || col.dataType == "bigint" {
-				query += fmt.Sprintf(" DEFAULT %s", *col.defaultValue)
-			} else {
-				query += fmt.Sprintf(" DEFAULT '%s'", *col.defaultValue)
-			}
+			change += fmt.Sprintf(" DEFAULT %s", columnDefault(d, col))
+		} else if col.existing != nil && col.existing.Default != nil {
+			// MODIFY COLUMN replaces the whole definition, so the current
+			// default has to be restated to be kept.
+			change += fmt.Sprintf(" DEFAULT %s", *col.existing.Default)
+		}
+		if col.comment != "" {
+			change += " COMMENT " + quoteLiteral(d, col.comment)
+		} else if col.existing != nil && col.existing.Comment != "" {
+			change += " COMMENT " + quoteLiteral(d, col.existing.Comment)
+		}
+		if col.check != "" {
+			change += fmt.Sprintf(" CHECK (%s)", col.check)
 		}
 		if col.afterColumn != nil {
-			query += fmt.Sprintf(" AFTER %s", *col.afterColumn)
+			change += fmt.Sprintf(" AFTER %s", d.QuoteIdentifier(*col.afterColumn))
 		}
-		sqls = append(sqls, query)
+		changes = append(changes, change)
 	}
-	return sqls
+	for _, fk := range tb.foreignKeys {
+		changes = append(changes, "ADD "+foreignKeyDefinition(d, tb.tableName, fk, true))
+	}
+	for _, name := range tb.droppedChecks {
+		changes = append(changes, "DROP CHECK "+d.QuoteIdentifier(name))
+	}
+	for _, check := range tb.checks {
+		changes = append(changes, "ADD "+checkDefinition(d, check))
+	}
+	changes = append(changes, options...)
+
+	query := fmt.Sprintf("ALTER TABLE %s %s", d.QuoteIdentifier(tb.tableName), strings.Join(changes, ", "))
+	return append([]string{query + mysqlAlterOptions(tb)}, indexStatements(d, tb)...), nil
+}
+
+func mysqlAlterOptions(tb *TableBuilder) string {
+	var options string
+	if tb.algorithm != "" {
+		options += ", ALGORITHM=" + tb.algorithm
+	}
+	if tb.lock != "" {
+		options += ", LOCK=" + tb.lock
+	}
+	return options
 }
 
 func (d *MySQLDialect) BuildDropTable(tableName string) string {
-	return fmt.Sprintf("DROP TABLE IF EXISTS %s", tableName)
+	return fmt.Sprintf("DROP TABLE IF EXISTS %s", d.QuoteIdentifier(tableName))
 }
 
 func (d *MySQLDialect) BuildDropColumn(tableName, columnName string) string {
-	return fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s", tableName, columnName)
+	return fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s", d.QuoteIdentifier(tableName), d.QuoteIdentifier(columnName))
 }
 
 func (d *SQLiteDialect) GetDataType(col *Column) string {
 	switch col.dataType {
-	case "uuid", "string":
+	case "uuid", "string", "char":
 		return "TEXT"
-	case "text":
+	case "text", "mediumtext", "longtext":
 		return "TEXT"
-	case "integer":
-		return "INTEGER"
-	case "bigint":
+	case "integer", "bigint", "smallint", "tinyint", "year":
 		return "INTEGER"
+	case "float", "double":
+		return "REAL"
 	case "boolean":
 		return "INTEGER"
-	case "timestamp", "date":
+	case "timestamp", "datetime", "date", "time":
 		return "TEXT"
-	case "json":
+	case "binary", "blob":
+		return "BLOB"
+	case "ipaddress", "macaddress":
+		return "TEXT"
+	case "json", "jsonb":
+		return "TEXT"
+	case "enum":
 		return "TEXT"
 	default:
 		if strings.HasPrefix(col.dataType, "decimal") {
@@ -278,13 +469,13 @@ func (d *SQLiteDialect) GetDataType(col *Column) string {
 	}
 }
 
-func (d *SQLiteDialect) BuildCreateTable(tb *TableBuilder) string {
+func (d *SQLiteDialect) BuildCreateTable(tb *TableBuilder) ([]string, error) {
 	var parts []string
-	parts = append(parts, fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (", tb.tableName))
+	parts = append(parts, fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (", d.QuoteIdentifier(tb.tableName)))
 
 	var columnDefs []string
 	for _, col := range tb.columns {
-		def := fmt.Sprintf("  %s %s", col.name, d.GetDataType(col))
+		def := fmt.Sprintf("  %s %s%s%s", d.QuoteIdentifier(col.name), d.GetDataType(col), collationClause(d, col), generatedClause(col))
 
 		if col.primary {
 			def += " PRIMARY KEY"
@@ -299,59 +490,69 @@ func (d *SQLiteDialect) BuildCreateTable(tb *TableBuilder) string {
 			def += " UNIQUE"
 		}
 		if col.defaultValue != nil {
-			if col.dataType == "boolean" || col.dataType == "integer" || col.dataType == "bigint" {
-				def += fmt.Sprintf(" DEFAULT %s", *col.defaultValue)
-			} else {
-				def += fmt.Sprintf(" DEFAULT '%s'", *col.defaultValue)
-			}
+			def += fmt.Sprintf(" DEFAULT %s", columnDefault(d, col))
+		}
+		if col.check != "" {
+			def += fmt.Sprintf(" CHECK (%s)", col.check)
 		}
+		def += d.enumCheck(col)
 		columnDefs = append(columnDefs, def)
 	}
 
-	for _, fk := range tb.foreignKeys {
-		fkDef := fmt.Sprintf("  FOREIGN KEY (%s) REFERENCES %s(%s)",
-			fk.column, fk.refTable, fk.refColumn)
+	if len(tb.primaryKey) > 0 {
+		columnDefs = append(columnDefs, fmt.Sprintf("  PRIMARY KEY (%s)", quoteColumns(d, tb.primaryKey)))
+	}
 
-		if fk.onDelete != "" {
-			fkDef += fmt.Sprintf(" ON DELETE %s", strings.ToUpper(fk.onDelete))
-		}
-		if fk.onUpdate != "" {
-			fkDef += fmt.Sprintf(" ON UPDATE %s", strings.ToUpper(fk.onUpdate))
-		}
-		columnDefs = append(columnDefs, fkDef)
+	for _, fk := range tb.foreignKeys {
+		columnDefs = append(columnDefs, "  "+foreignKeyDefinition(d, tb.tableName, fk, fk.name != ""))
+	}
+	for _, check := range tb.checks {
+		columnDefs = append(columnDefs, "  "+checkDefinition(d, check))
 	}
 
 	parts = append(parts, strings.Join(columnDefs, ",\n"))
 	parts = append(parts, ");")
 
-	return strings.Join(parts, "\n")
+	return append([]string{strings.Join(parts, "\n")}, indexStatements(d, tb)...), nil
 }
 
-func (d *SQLiteDialect) BuildModifyTable(tb *TableBuilder) []string {
+func (d *SQLiteDialect) BuildModifyTable(tb *TableBuilder) ([]string, error) {
 	var sqls []string
-	for _, col := range tb.columns {
-		query := fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s",
-			tb.tableName, col.name, d.GetDataType(col))
-
-		if !col.nullable {
-			query += " NOT NULL"
-		}
-		if col.defaultValue != nil {
-			if col.dataType == "boolean" || col.dataType == "integer" || col.dataType == "bigint" {
-				query += fmt.Sprintf(" DEFAULT %s", *col.defaultValue)
-			} else {
-				query += fmt.Sprintf(" DEFAULT '%s'", *col.defaultValue)
-			}
+	if d.needsRebuild(tb) {
+		rebuild, err := d.rebuildTable(tb, nil)
+		if err != nil {
+			return nil, err
+		}
+		sqls = rebuild
+	} else {
+		for _, col := range tb.columns {
+			query := fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s",
+				d.QuoteIdentifier(tb.tableName), d.columnDefinition(col))
+			sqls = append(sqls, query)
 		}
-		sqls = append(sqls, query)
 	}
-	return sqls
+	return append(sqls, indexStatements(d, tb)...), nil
+}
+
+func (d *SQLiteDialect) columnDefinition(col *Column) string {
+	def := fmt.Sprintf("%s %s%s%s", d.QuoteIdentifier(col.name), d.GetDataType(col), collationClause(d, col), generatedClause(col))
+
+	if !col.nullable {
+		def += " NOT NULL"
+	}
+	if col.defaultValue != nil {
+		def += fmt.Sprintf(" DEFAULT %s", columnDefault(d, col))
+	}
+	if col.check != "" {
+		def += fmt.Sprintf(" CHECK (%s)", col.check)
+	}
+	return def + d.enumCheck(col)
 }
 
 func (d *SQLiteDialect) BuildDropTable(tableName string) string {
-	return fmt.Sprintf("DROP TABLE IF EXISTS %s", tableName)
+	return fmt.Sprintf("DROP TABLE IF EXISTS %s", d.QuoteIdentifier(tableName))
 }
 
 func (d *SQLiteDialect) BuildDropColumn(tableName, columnName string) string {
-	return fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s", tableName, columnName)
+	return fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s", d.QuoteIdentifier(tableName), d.QuoteIdentifier(columnName))
 }
@@ -24,6 +24,23 @@ func TestPostgresDialectDataTypes(t *testing.T) {
 		{&Column{dataType: "decimal(10,2)"}, "DECIMAL(10,2)"},
 		{&Column{dataType: "integer", autoIncrement: true}, "SERIAL"},
 		{&Column{dataType: "bigint", autoIncrement: true}, "BIGSERIAL"},
+		{&Column{dataType: "string", length: 100}, "VARCHAR(100)"},
+		{&Column{dataType: "char", length: 2}, "CHAR(2)"},
+		{&Column{dataType: "smallint"}, "SMALLINT"},
+		{&Column{dataType: "smallint", autoIncrement: true}, "SMALLSERIAL"},
+		{&Column{dataType: "tinyint"}, "SMALLINT"},
+		{&Column{dataType: "float"}, "REAL"},
+		{&Column{dataType: "double"}, "DOUBLE PRECISION"},
+		{&Column{dataType: "mediumtext"}, "TEXT"},
+		{&Column{dataType: "longtext"}, "TEXT"},
+		{&Column{dataType: "binary", length: 16}, "BYTEA"},
+		{&Column{dataType: "blob"}, "BYTEA"},
+		{&Column{dataType: "time"}, "TIME"},
+		{&Column{dataType: "datetime"}, "TIMESTAMP"},
+		{&Column{dataType: "year"}, "SMALLINT"},
+		{&Column{dataType: "ipaddress"}, "INET"},
+		{&Column{dataType: "macaddress"}, "MACADDR"},
+		{&Column{dataType: "jsonb"}, "JSONB"},
 	}
 
 	for _, tt := range tests {
@@ -51,6 +68,23 @@ func TestMySQLDialectDataTypes(t *testing.T) {
 		{&Column{dataType: "date"}, "DATE"},
 		{&Column{dataType: "json"}, "JSON"},
 		{&Column{dataType: "decimal(10,2)"}, "DECIMAL(10,2)"},
+		{&Column{dataType: "string", length: 100}, "VARCHAR(100)"},
+		{&Column{dataType: "char", length: 2}, "CHAR(2)"},
+		{&Column{dataType: "smallint"}, "SMALLINT"},
+		{&Column{dataType: "tinyint"}, "TINYINT"},
+		{&Column{dataType: "float"}, "FLOAT"},
+		{&Column{dataType: "double"}, "DOUBLE"},
+		{&Column{dataType: "mediumtext"}, "MEDIUMTEXT"},
+		{&Column{dataType: "longtext"}, "LONGTEXT"},
+		{&Column{dataType: "binary"}, "VARBINARY(255)"},
+		{&Column{dataType: "binary", length: 16}, "VARBINARY(16)"},
+		{&Column{dataType: "blob"}, "BLOB"},
+		{&Column{dataType: "time"}, "TIME"},
+		{&Column{dataType: "datetime"}, "DATETIME"},
+		{&Column{dataType: "year"}, "YEAR"},
+		{&Column{dataType: "ipaddress"}, "VARCHAR(45)"},
+		{&Column{dataType: "macaddress"}, "VARCHAR(17)"},
+		{&Column{dataType: "jsonb"}, "JSON"},
 	}
 
 	for _, tt := range tests {
@@ -78,6 +112,19 @@ func TestSQLiteDialectDataTypes(t *testing.T) {
 		{&Column{dataType: "date"}, "TEXT"},
 		{&Column{dataType: "json"}, "TEXT"},
 		{&Column{dataType: "decimal(10,2)"}, "REAL"},
+		{&Column{dataType: "char", length: 2}, "TEXT"},
+		{&Column{dataType: "smallint"}, "INTEGER"},
+		{&Column{dataType: "tinyint"}, "INTEGER"},
+		{&Column{dataType: "float"}, "REAL"},
+		{&Column{dataType: "double"}, "REAL"},
+		{&Column{dataType: "longtext"}, "TEXT"},
+		{&Column{dataType: "binary"}, "BLOB"},
+		{&Column{dataType: "blob"}, "BLOB"},
+		{&Column{dataType: "time"}, "TEXT"},
+		{&Column{dataType: "datetime"}, "TEXT"},
+		{&Column{dataType: "year"}, "INTEGER"},
+		{&Column{dataType: "ipaddress"}, "TEXT"},
+		{&Column{dataType: "jsonb"}, "TEXT"},
 	}
 
 	for _, tt := range tests {
@@ -88,6 +135,62 @@ func TestSQLiteDialectDataTypes(t *testing.T) {
 	}
 }
 
+func TestColumnTypesCreateTableSQL(t *testing.T) {
+	tb := &TableBuilder{tableName: "devices"}
+	currentBuilder = tb
+	String("serial", 64)
+	Char("country", 2)
+	TinyInteger("priority")
+	Double("latitude")
+	Binary("fingerprint", 32)
+	DateTime("seen_at")
+	IpAddress("ip")
+	MacAddress("mac")
+	currentBuilder = nil
+
+	tests := []struct {
+		dialect  Dialect
+		expected string
+	}{
+		{&PostgresDialect{}, `CREATE TABLE IF NOT EXISTS devices (
+  serial VARCHAR(64) NOT NULL,
+  country CHAR(2) NOT NULL,
+  priority SMALLINT NOT NULL,
+  latitude DOUBLE PRECISION NOT NULL,
+  fingerprint BYTEA NOT NULL,
+  seen_at TIMESTAMP NOT NULL,
+  ip INET NOT NULL,
+  mac MACADDR NOT NULL
+);`},
+		{&MySQLDialect{}, `CREATE TABLE IF NOT EXISTS devices (
+  serial VARCHAR(64) NOT NULL,
+  country CHAR(2) NOT NULL,
+  priority TINYINT NOT NULL,
+  latitude DOUBLE NOT NULL,
+  fingerprint VARBINARY(32) NOT NULL,
+  seen_at DATETIME NOT NULL,
+  ip VARCHAR(45) NOT NULL,
+  mac VARCHAR(17) NOT NULL
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;`},
+		{&SQLiteDialect{}, `CREATE TABLE IF NOT EXISTS devices (
+  serial TEXT NOT NULL,
+  country TEXT NOT NULL,
+  priority INTEGER NOT NULL,
+  latitude REAL NOT NULL,
+  fingerprint BLOB NOT NULL,
+  seen_at TEXT NOT NULL,
+  ip TEXT NOT NULL,
+  mac TEXT NOT NULL
+);`},
+	}
+
+	for _, tt := range tests {
+		if sql := buildCreateTable(t, tt.dialect, tb); sql != tt.expected {
+			t.Errorf("%T: expected\n%s\ngot\n%s", tt.dialect, tt.expected, sql)
+		}
+	}
+}
+
 func TestPostgresCreateTableSQL(t *testing.T) {
 	dialect := &PostgresDialect{}
 
@@ -100,7 +203,7 @@ func TestPostgresCreateTableSQL(t *testing.T) {
 		},
 	}
 
-	sql := dialect.BuildCreateTable(tb)
+	sql := buildCreateTable(t, dialect, tb)
 
 	if !strings.Contains(sql, "CREATE TABLE IF NOT EXISTS users") {
 		t.Error("SQL should contain CREATE TABLE IF NOT EXISTS users")
@@ -130,7 +233,7 @@ func TestMySQLCreateTableSQL(t *testing.T) {
 		},
 	}
 
-	sql := dialect.BuildCreateTable(tb)
+	sql := buildCreateTable(t, dialect, tb)
 
 	if !strings.Contains(sql, "CREATE TABLE IF NOT EXISTS users") {
 		t.Error("SQL should contain CREATE TABLE IF NOT EXISTS users")
@@ -156,7 +259,7 @@ func TestSQLiteCreateTableSQL(t *testing.T) {
 		},
 	}
 
-	sql := dialect.BuildCreateTable(tb)
+	sql := buildCreateTable(t, dialect, tb)
 
 	if !strings.Contains(sql, "CREATE TABLE IF NOT EXISTS users") {
 		t.Error("SQL should contain CREATE TABLE IF NOT EXISTS users")
@@ -177,16 +280,16 @@ func TestForeignKeySQL(t *testing.T) {
 		},
 		foreignKeys: []*ForeignKey{
 			{
-				column:    "business_id",
-				refTable:  "businesses",
-				refColumn: "id",
-				onDelete:  "cascade",
-				onUpdate:  "restrict",
+				columns:    []string{"business_id"},
+				refTable:   "businesses",
+				refColumns: []string{"id"},
+				onDelete:   "cascade",
+				onUpdate:   "restrict",
 			},
 		},
 	}
 
-	sql := dialect.BuildCreateTable(tb)
+	sql := buildCreateTable(t, dialect, tb)
 
 	if !strings.Contains(sql, "FOREIGN KEY") {
 		t.Error("SQL should contain FOREIGN KEY")
@@ -205,22 +308,199 @@ func TestForeignKeySQL(t *testing.T) {
 	}
 }
 
+func TestCompositeForeignKeySQL(t *testing.T) {
+	fk := &ForeignKey{
+		columns:    []string{"order_id", "line"},
+		refTable:   "order_lines",
+		refColumns: []string{"order_id", "line"},
+		deferrable: true,
+	}
+	tb := &TableBuilder{
+		tableName:   "shipments",
+		columns:     []*Column{{name: "id", dataType: "integer", primary: true}},
+		foreignKeys: []*ForeignKey{fk},
+	}
+
+	sql := buildCreateTable(t, &PostgresDialect{}, tb)
+	expected := "CONSTRAINT fk_shipments_order_id_line FOREIGN KEY (order_id, line) REFERENCES order_lines(order_id, line) DEFERRABLE INITIALLY DEFERRED"
+	if !strings.Contains(sql, expected) {
+		t.Errorf("Expected %q in:\n%s", expected, sql)
+	}
+
+	fk.name = "shipments_line_fk"
+	sql = buildCreateTable(t, &SQLiteDialect{}, tb)
+	if !strings.Contains(sql, "CONSTRAINT shipments_line_fk FOREIGN KEY (order_id, line)") {
+		t.Errorf("Expected a named constraint in:\n%s", sql)
+	}
+}
+
+func TestAddForeignKeySQL(t *testing.T) {
+	tb := &TableBuilder{
+		tableName: "posts",
+		columns:   []*Column{{name: "author_id", dataType: "integer", nullable: true}},
+		foreignKeys: []*ForeignKey{
+			{columns: []string{"author_id"}, refTable: "users", refColumns: []string{"id"}, onDelete: "set null"},
+		},
+	}
+
+	sqls, err := (&PostgresDialect{}).BuildModifyTable(tb)
+	if err != nil {
+		t.Fatalf("Failed to build SQL: %v", err)
+	}
+	expected := "ALTER TABLE posts ADD CONSTRAINT fk_posts_author_id FOREIGN KEY (author_id) REFERENCES users(id) ON DELETE SET NULL"
+	if len(sqls) != 2 || sqls[1] != expected {
+		t.Errorf("Expected %q, got %v", expected, sqls)
+	}
+
+	sqls, err = (&MySQLDialect{}).BuildModifyTable(tb)
+	if err != nil {
+		t.Fatalf("Failed to build SQL: %v", err)
+	}
+	expected = "ALTER TABLE posts ADD COLUMN author_id INT, ADD CONSTRAINT fk_posts_author_id FOREIGN KEY (author_id) REFERENCES users(id) ON DELETE SET NULL"
+	if len(sqls) != 1 || sqls[0] != expected {
+		t.Errorf("Expected %q, got %v", expected, sqls)
+	}
+}
+
+func TestCheckConstraintSQL(t *testing.T) {
+	tb := &TableBuilder{
+		tableName: "products",
+		columns:   []*Column{{name: "price", dataType: "integer", check: "price >= 0"}},
+		checks:    []*tableCheck{{name: "products_status_check", expression: "status IN ('draft', 'live')"}},
+	}
+
+	for _, dialect := range []Dialect{&PostgresDialect{}, &MySQLDialect{}, &SQLiteDialect{}} {
+		sql := buildCreateTable(t, dialect, tb)
+		if !strings.Contains(sql, "price INT") || !strings.Contains(sql, "CHECK (price >= 0)") {
+			t.Errorf("%T: expected an inline CHECK in:\n%s", dialect, sql)
+		}
+		if !strings.Contains(sql, "CONSTRAINT products_status_check CHECK (status IN ('draft', 'live'))") {
+			t.Errorf("%T: expected a named CHECK in:\n%s", dialect, sql)
+		}
+	}
+
+	modify := &TableBuilder{
+		tableName:     "products",
+		checks:        []*tableCheck{{name: "products_price_check", expression: "price < 1000"}},
+		droppedChecks: []string{"products_status_check"},
+	}
+	sqls, err := (&PostgresDialect{}).BuildModifyTable(modify)
+	if err != nil {
+		t.Fatalf("Failed to build SQL: %v", err)
+	}
+	expected := []string{
+		"ALTER TABLE products DROP CONSTRAINT products_status_check",
+		"ALTER TABLE products ADD CONSTRAINT products_price_check CHECK (price < 1000)",
+	}
+	if strings.Join(sqls, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Expected %v, got %v", expected, sqls)
+	}
+
+	sqls, err = (&MySQLDialect{}).BuildModifyTable(modify)
+	if err != nil {
+		t.Fatalf("Failed to build SQL: %v", err)
+	}
+	if len(sqls) != 1 || sqls[0] != "ALTER TABLE products DROP CHECK products_status_check, ADD CONSTRAINT products_price_check CHECK (price < 1000)" {
+		t.Errorf("Unexpected SQL: %v", sqls)
+	}
+}
+
+func TestEnumSQL(t *testing.T) {
+	tb := &TableBuilder{tableName: "orders"}
+	currentBuilder = tb
+	Enum("status", []string{"pending", "shipped"}).Default("pending")
+	currentBuilder = nil
+
+	sqls, err := (&PostgresDialect{}).BuildCreateTable(tb)
+	if err != nil {
+		t.Fatalf("Failed to build SQL: %v", err)
+	}
+	if len(sqls) != 2 || sqls[0] != "DO $enum$ BEGIN CREATE TYPE orders_status_enum AS ENUM ('pending', 'shipped'); EXCEPTION WHEN duplicate_object THEN NULL; END $enum$" ||
+		!strings.Contains(sqls[1], "status orders_status_enum NOT NULL DEFAULT 'pending'") {
+		t.Errorf("Unexpected Postgres SQL: %v", sqls)
+	}
+
+	sql := buildCreateTable(t, &MySQLDialect{}, tb)
+	if !strings.Contains(sql, "status ENUM('pending', 'shipped') NOT NULL") {
+		t.Errorf("Unexpected MySQL SQL: %s", sql)
+	}
+
+	sql = buildCreateTable(t, &SQLiteDialect{}, tb)
+	if !strings.Contains(sql, "status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'shipped'))") {
+		t.Errorf("Unexpected SQLite SQL: %s", sql)
+	}
+
+	col := tb.columns[0]
+	col.change = true
+	col.defaultValue = nil
+	col.enumValues = []string{"pending", "packed", "shipped"}
+	col.existing = &ColumnInfo{Name: "status", Type: "USER-DEFINED"}
+	sqls, err = (&PostgresDialect{}).BuildModifyTable(tb)
+	if err != nil {
+		t.Fatalf("Failed to build SQL: %v", err)
+	}
+	expected := []string{
+		"ALTER TYPE orders_status_enum ADD VALUE IF NOT EXISTS 'pending'",
+		"ALTER TYPE orders_status_enum ADD VALUE IF NOT EXISTS 'packed' AFTER 'pending'",
+		"ALTER TYPE orders_status_enum ADD VALUE IF NOT EXISTS 'shipped' AFTER 'packed'",
+	}
+	if strings.Join(sqls, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Expected %v, got %v", expected, sqls)
+	}
+
+	col.existing = &ColumnInfo{Name: "status", Type: "character varying(255)"}
+	sqls, err = (&PostgresDialect{}).BuildModifyTable(tb)
+	if err != nil {
+		t.Fatalf("Failed to build SQL: %v", err)
+	}
+	if len(sqls) != 2 || sqls[1] != "ALTER TABLE orders ALTER COLUMN status TYPE orders_status_enum USING status::text::orders_status_enum" {
+		t.Errorf("Unexpected conversion SQL: %v", sqls)
+	}
+}
+
+func TestCompositePrimaryKeySQL(t *testing.T) {
+	tb := &TableBuilder{
+		tableName: "role_user",
+		columns: []*Column{
+			{name: "user_id", dataType: "integer"},
+			{name: "key", dataType: "string"},
+		},
+		primaryKey: []string{"user_id", "key"},
+	}
+
+	tests := []struct {
+		dialect  Dialect
+		expected string
+	}{
+		{&PostgresDialect{}, "PRIMARY KEY (user_id, key)"},
+		{&MySQLDialect{}, "PRIMARY KEY (user_id, `key`)"},
+		{&SQLiteDialect{}, `PRIMARY KEY (user_id, "key")`},
+	}
+
+	for _, tt := range tests {
+		sql := buildCreateTable(t, tt.dialect, tb)
+		if !strings.Contains(sql, tt.expected) {
+			t.Errorf("%T: SQL should contain %s, got:\n%s", tt.dialect, tt.expected, sql)
+		}
+		if strings.Count(sql, "PRIMARY KEY") != 1 {
+			t.Errorf("%T: expected a single PRIMARY KEY clause, got:\n%s", tt.dialect, sql)
+		}
+	}
+}
+
 func TestDefaultValuesSQL(t *testing.T) {
 	dialect := &PostgresDialect{}
 
-	trueVal := "true"
-	oneVal := "1"
-
 	tb := &TableBuilder{
 		tableName: "users",
 		columns: []*Column{
 			{name: "id", dataType: "uuid", primary: true},
-			{name: "active", dataType: "boolean", defaultValue: &trueVal},
-			{name: "status", dataType: "integer", defaultValue: &oneVal},
+			{name: "active", dataType: "boolean", defaultValue: true},
+			{name: "status", dataType: "integer", defaultValue: 1},
 		},
 	}
 
-	sql := dialect.BuildCreateTable(tb)
+	sql := buildCreateTable(t, dialect, tb)
 
 	if !strings.Contains(sql, "DEFAULT true") {
 		t.Error("SQL should contain DEFAULT true for boolean")
@@ -241,7 +521,10 @@ func TestModifyTableSQL(t *testing.T) {
 		},
 	}
 
-	sqls := dialect.BuildModifyTable(tb)
+	sqls, err := dialect.BuildModifyTable(tb)
+	if err != nil {
+		t.Fatalf("Failed to build modify table SQL: %v", err)
+	}
 
 	if len(sqls) == 0 {
 		t.Error("Should return at least one SQL statement")
@@ -263,13 +546,110 @@ func TestMySQLAfterColumn(t *testing.T) {
 		},
 	}
 
-	sqls := dialect.BuildModifyTable(tb)
+	sqls, err := dialect.BuildModifyTable(tb)
+	if err != nil {
+		t.Fatalf("Failed to build modify table SQL: %v", err)
+	}
 
 	if !strings.Contains(sqls[0], "AFTER name") {
 		t.Error("SQL should contain AFTER name")
 	}
 }
 
+func TestMySQLModifyTableSingleStatement(t *testing.T) {
+	dialect := &MySQLDialect{}
+
+	tb := (&TableBuilder{
+		tableName: "users",
+		columns: []*Column{
+			{name: "age", dataType: "integer", nullable: true},
+			{name: "status", dataType: "string"},
+		},
+	}).Algorithm("instant").Lock("none")
+
+	sqls, err := dialect.BuildModifyTable(tb)
+	if err != nil {
+		t.Fatalf("Failed to build modify table SQL: %v", err)
+	}
+
+	expected := "ALTER TABLE users ADD COLUMN age INT, ADD COLUMN status VARCHAR(255) NOT NULL, ALGORITHM=INSTANT, LOCK=NONE"
+	if len(sqls) != 1 || sqls[0] != expected {
+		t.Errorf("Expected [%s], got %v", expected, sqls)
+	}
+}
+
+func TestAlterOptionsValidation(t *testing.T) {
+	tb := (&TableBuilder{tableName: "users"}).Algorithm("fast")
+	if err := tb.validateAlterOptions(); err == nil {
+		t.Error("Expected an error for an unknown algorithm")
+	}
+
+	tb = (&TableBuilder{tableName: "users"}).Algorithm("inplace").Lock("shared")
+	if err := tb.validateAlterOptions(); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestChangeColumnSQL(t *testing.T) {
+	active := "'active'"
+	tb := &TableBuilder{
+		tableName: "users",
+		columns: []*Column{
+			{name: "name", dataType: "text", nullable: true, nullableSet: true, change: true,
+				existing: &ColumnInfo{Name: "name", Type: "varchar(255)"}},
+			{name: "status", dataType: "string", change: true,
+				existing: &ColumnInfo{Name: "status", Type: "varchar(100)", Default: &active}},
+		},
+	}
+
+	sqls, err := (&PostgresDialect{}).BuildModifyTable(tb)
+	if err != nil {
+		t.Fatalf("Failed to build modify table SQL: %v", err)
+	}
+	expected := []string{
+		"ALTER TABLE users ALTER COLUMN name TYPE TEXT USING name::TEXT, ALTER COLUMN name DROP NOT NULL",
+		"ALTER TABLE users ALTER COLUMN status TYPE VARCHAR(255) USING status::VARCHAR(255)",
+	}
+	if len(sqls) != len(expected) {
+		t.Fatalf("Expected %d statements, got %v", len(expected), sqls)
+	}
+	for i := range expected {
+		if sqls[i] != expected[i] {
+			t.Errorf("Expected %s, got %s", expected[i], sqls[i])
+		}
+	}
+
+	sqls, err = (&MySQLDialect{}).BuildModifyTable(tb)
+	if err != nil {
+		t.Fatalf("Failed to build modify table SQL: %v", err)
+	}
+	mysql := "ALTER TABLE users MODIFY COLUMN name TEXT, MODIFY COLUMN status VARCHAR(255) NOT NULL DEFAULT 'active'"
+	if len(sqls) != 1 || sqls[0] != mysql {
+		t.Errorf("Expected [%s], got %v", mysql, sqls)
+	}
+}
+
+func TestMySQLChangeKeepsAutoIncrement(t *testing.T) {
+	tb := &TableBuilder{
+		tableName: "users",
+		columns: []*Column{
+			{name: "id", dataType: "bigint", autoIncrement: true, change: true,
+				existing: &ColumnInfo{Name: "id", Type: "int", Primary: true}},
+			{name: "legacy_id", dataType: "bigint", change: true,
+				existing: &ColumnInfo{Name: "legacy_id", Type: "int", AutoIncrement: true}},
+		},
+	}
+
+	sqls, err := (&MySQLDialect{}).BuildModifyTable(tb)
+	if err != nil {
+		t.Fatalf("Failed to build modify table SQL: %v", err)
+	}
+	expected := "ALTER TABLE users MODIFY COLUMN id BIGINT AUTO_INCREMENT NOT NULL, MODIFY COLUMN legacy_id BIGINT AUTO_INCREMENT NOT NULL"
+	if len(sqls) != 1 || sqls[0] != expected {
+		t.Errorf("Expected [%s], got %v", expected, sqls)
+	}
+}
+
 func TestDropTableSQL(t *testing.T) {
 	dialects := []Dialect{
 		&PostgresDialect{},
@@ -299,3 +679,12 @@ func TestDropColumnSQL(t *testing.T) {
 		}
 	}
 }
+
+func buildCreateTable(t *testing.T, dialect Dialect, tb *TableBuilder) string {
+	t.Helper()
+	sqls, err := dialect.BuildCreateTable(tb)
+	if err != nil {
+		t.Fatalf("Failed to build SQL: %v", err)
+	}
+	return strings.Join(sqls, ";\n")
+}
@@ -0,0 +1,118 @@
+package olympian
+
+import (
+	"fmt"
+	"strings"
+)
+
+func (tb *TableBuilder) addEnum(name string, values []string) *ColumnBuilder {
+	cb := tb.addColumn(name, "enum")
+	cb.column.enumValues = values
+	if tb != nil {
+		cb.column.enumType = enumTypeName(tb.tableName, name)
+	}
+	return cb
+}
+
+// Enum adds a column restricted to values. MySQL uses ENUM, PostgreSQL a
+// <table>_<column>_enum type created before the table, and SQLite TEXT with
+// a CHECK constraint. With Change, new values are added to the column.
+func Enum(name string, values []string) *ColumnBuilder {
+	return currentBuilder.addEnum(name, values)
+}
+
+// enumTypeName returns <table>_<column>_enum, in the schema of the table.
+func enumTypeName(tableName, columnName string) string {
+	schema, table := splitTableName(tableName)
+	name := fmt.Sprintf("%s_%s_enum", table, columnName)
+	if schema != "" {
+		return schema + "." + name
+	}
+	return name
+}
+
+func quoteValues(values []string) string {
+	quoted := make([]string, len(values))
+	for i, value := range values {
+		quoted[i] = "'" + strings.ReplaceAll(value, "'", "''") + "'"
+	}
+	return strings.Join(quoted, ", ")
+}
+
+func (tb *TableBuilder) validateEnums() error {
+	for _, col := range tb.columns {
+		if col.dataType == "enum" && len(col.enumValues) == 0 {
+			return fmt.Errorf("column %s.%s: enums need at least one value", tb.tableName, col.name)
+		}
+	}
+	return nil
+}
+
+// createEnumTypes returns the CREATE TYPE statements for the new enum
+// columns of tb.
+func (d *PostgresDialect) createEnumTypes(tb *TableBuilder) []string {
+	var sqls []string
+	for _, col := range tb.columns {
+		if col.dataType == "enum" && !col.change {
+			sqls = append(sqls, d.createEnumType(d.QuoteIdentifier(col.enumType), col.enumValues))
+		}
+	}
+	return sqls
+}
+
+// createEnumType creates an enum type unless it exists, which PostgreSQL's
+// CREATE TYPE has no IF NOT EXISTS for. It keeps Create as safe to re-run as
+// the CREATE TABLE IF NOT EXISTS that follows.
+func (d *PostgresDialect) createEnumType(name string, values []string) string {
+	return fmt.Sprintf("DO $enum$ BEGIN CREATE TYPE %s AS ENUM (%s); EXCEPTION WHEN duplicate_object THEN NULL; END $enum$",
+		name, quoteValues(values))
+}
+
+// addEnumValues adds the values of a changed enum column that the type does
+// not have yet, each after the value preceding it. Values are never removed.
+func (d *PostgresDialect) addEnumValues(col *Column) []string {
+	var sqls []string
+	for i, value := range col.enumValues {
+		query := fmt.Sprintf("ALTER TYPE %s ADD VALUE IF NOT EXISTS %s", d.QuoteIdentifier(col.enumType), quoteValues([]string{value}))
+		if i > 0 {
+			query += " AFTER " + quoteValues([]string{col.enumValues[i-1]})
+		}
+		sqls = append(sqls, query)
+	}
+	return sqls
+}
+
+// postgresEnumTypes returns the enum types Enum created for the columns of a
+// table, so they can be dropped along with it.
+func postgresEnumTypes(q Querier, tableName string) ([]string, error) {
+	schema, table := splitTableName(tableName)
+	types, err := queryStrings(q, `SELECT DISTINCT t.typname
+		FROM pg_attribute a
+		JOIN pg_class c ON c.oid = a.attrelid
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		JOIN pg_type t ON t.oid = a.atttypid
+		WHERE c.relname = $1 AND n.nspname = COALESCE(NULLIF($2, ''), current_schema())
+			AND t.typtype = 'e' AND a.attnum > 0 AND NOT a.attisdropped
+		ORDER BY t.typname`, table, schema)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read enum types of table %s: %w", tableName, err)
+	}
+
+	var owned []string
+	for _, name := range types {
+		if strings.HasPrefix(name, table+"_") && strings.HasSuffix(name, "_enum") {
+			if schema != "" {
+				name = schema + "." + name
+			}
+			owned = append(owned, name)
+		}
+	}
+	return owned, nil
+}
+
+func (d *SQLiteDialect) enumCheck(col *Column) string {
+	if col.dataType != "enum" {
+		return ""
+	}
+	return fmt.Sprintf(" CHECK (%s IN (%s))", d.QuoteIdentifier(col.name), quoteValues(col.enumValues))
+}
@@ -0,0 +1,50 @@
+package olympian
+
+import "fmt"
+
+// StoredAs makes the column a generated column computed from expression and
+// stored on write.
+func (cb *ColumnBuilder) StoredAs(expression string) *ColumnBuilder {
+	cb.column.generatedAs = expression
+	cb.column.virtual = false
+	return cb
+}
+
+// VirtualAs makes the column a generated column computed from expression
+// when read. PostgreSQL does not support virtual columns, use StoredAs.
+func (cb *ColumnBuilder) VirtualAs(expression string) *ColumnBuilder {
+	cb.column.generatedAs = expression
+	cb.column.virtual = true
+	return cb
+}
+
+func generatedClause(col *Column) string {
+	if col.generatedAs == "" {
+		return ""
+	}
+	kind := "STORED"
+	if col.virtual {
+		kind = "VIRTUAL"
+	}
+	return fmt.Sprintf(" GENERATED ALWAYS AS (%s) %s", col.generatedAs, kind)
+}
+
+func (tb *TableBuilder) validateGeneratedColumns() error {
+	_, isPostgres := tb.dialect.(*PostgresDialect)
+	for _, col := range tb.columns {
+		if col.generatedAs == "" {
+			continue
+		}
+		switch {
+		case col.virtual && isPostgres:
+			return fmt.Errorf("column %s.%s: PostgreSQL does not support virtual generated columns, use StoredAs", tb.tableName, col.name)
+		case col.defaultValue != nil:
+			return fmt.Errorf("column %s.%s: generated columns cannot have a default", tb.tableName, col.name)
+		case col.autoIncrement:
+			return fmt.Errorf("column %s.%s: generated columns cannot auto increment", tb.tableName, col.name)
+		case col.change:
+			return fmt.Errorf("column %s.%s: generated columns cannot be changed, drop and add the column instead", tb.tableName, col.name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package olympian
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGeneratedColumnSQL(t *testing.T) {
+	tb := &TableBuilder{
+		tableName: "users",
+		columns: []*Column{
+			{name: "first_name", dataType: "string"},
+			{name: "last_name", dataType: "string"},
+			{name: "full_name", dataType: "string", nullable: true, generatedAs: "CONCAT(first_name, ' ', last_name)"},
+			{name: "initials", dataType: "string", nullable: true, generatedAs: "substr(first_name, 1, 1)", virtual: true},
+		},
+	}
+
+	tests := []struct {
+		dialect  Dialect
+		expected []string
+	}{
+		{&MySQLDialect{}, []string{
+			"full_name VARCHAR(255) GENERATED ALWAYS AS (CONCAT(first_name, ' ', last_name)) STORED,",
+			"initials VARCHAR(255) GENERATED ALWAYS AS (substr(first_name, 1, 1)) VIRTUAL",
+		}},
+		{&SQLiteDialect{}, []string{
+			"full_name TEXT GENERATED ALWAYS AS (CONCAT(first_name, ' ', last_name)) STORED,",
+			"initials TEXT GENERATED ALWAYS AS (substr(first_name, 1, 1)) VIRTUAL",
+		}},
+	}
+	for _, tt := range tests {
+		sql := buildCreateTable(t, tt.dialect, tb)
+		for _, fragment := range tt.expected {
+			if !strings.Contains(sql, fragment) {
+				t.Errorf("%T: SQL should contain %q, got:\n%s", tt.dialect, fragment, sql)
+			}
+		}
+	}
+
+	tb.columns = tb.columns[:3]
+	sqls, err := (&PostgresDialect{}).BuildModifyTable(tb)
+	if err != nil {
+		t.Fatalf("Failed to build SQL: %v", err)
+	}
+	expected := "ALTER TABLE users ADD COLUMN full_name VARCHAR(255) GENERATED ALWAYS AS (CONCAT(first_name, ' ', last_name)) STORED"
+	if len(sqls) != 3 || sqls[2] != expected {
+		t.Errorf("Expected %s, got %v", expected, sqls)
+	}
+}
+
+func TestGeneratedColumnValidation(t *testing.T) {
+	tests := []struct {
+		dialect Dialect
+		column  *Column
+	}{
+		{&PostgresDialect{}, &Column{name: "initials", dataType: "string", generatedAs: "left(name, 1)", virtual: true}},
+		{&MySQLDialect{}, &Column{name: "initials", dataType: "string", generatedAs: "left(name, 1)", defaultValue: "x"}},
+		{&MySQLDialect{}, &Column{name: "initials", dataType: "string", generatedAs: "left(name, 1)", change: true}},
+		{&SQLiteDialect{}, &Column{name: "total", dataType: "integer", generatedAs: "price * quantity", autoIncrement: true}},
+	}
+	for _, tt := range tests {
+		tb := &TableBuilder{scope: scope{dialect: tt.dialect}, tableName: "users", columns: []*Column{tt.column}}
+		if err := tb.validateGeneratedColumns(); err == nil {
+			t.Errorf("Expected an error for %+v on %T", tt.column, tt.dialect)
+		}
+	}
+}
+
+func TestGeneratedColumnsSQLite(t *testing.T) {
+	db := setupTestDB(t)
+	defer func() { _ = db.Close() }()
+
+	SetDB(db, &SQLiteDialect{})
+
+	if err := Table("users").Create(func() {
+		Integer("id").Primary()
+		String("first_name")
+		String("last_name")
+		String("full_name").VirtualAs("first_name || ' ' || last_name")
+	}); err != nil {
+		t.Fatalf("Failed to create table: %v", err)
+	}
+	if _, err := db.Exec("INSERT INTO users (id, first_name, last_name) VALUES (1, 'Ada', 'Lovelace')"); err != nil {
+		t.Fatalf("Failed to insert user: %v", err)
+	}
+
+	// Stored columns cannot be added with ALTER TABLE, so the table is
+	// rebuilt, which must not copy the virtual column.
+	if err := Table("users").Modify(func() {
+		String("initials").StoredAs("substr(first_name, 1, 1) || substr(last_name, 1, 1)")
+	}); err != nil {
+		t.Fatalf("Failed to add stored column: %v", err)
+	}
+
+	var fullName, initials string
+	if err := db.QueryRow("SELECT full_name, initials FROM users").Scan(&fullName, &initials); err != nil {
+		t.Fatalf("Failed to read user: %v", err)
+	}
+	if fullName != "Ada Lovelace" || initials != "AL" {
+		t.Errorf("Unexpected generated values %q and %q", fullName, initials)
+	}
+
+	for _, column := range []string{"full_name", "initials"} {
+		if ok, _ := HasColumn("users", column); !ok {
+			t.Errorf("Expected users.%s to exist", column)
+		}
+	}
+}
@@ -0,0 +1,429 @@
+package olympian
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// tableIndex is an index declared inside a Create or Modify closure.
+type tableIndex struct {
+	columns []string
+	name    string
+	unique  bool
+	options indexOptions
+}
+
+type IndexBuilder struct {
+	index *tableIndex
+}
+
+func (tb *TableBuilder) addIndex(columns []string, unique bool) *IndexBuilder {
+	index := &tableIndex{columns: columns, unique: unique}
+	if tb != nil {
+		tb.indexes = append(tb.indexes, index)
+	}
+	return &IndexBuilder{index: index}
+}
+
+// Index declares an index on the table, named <table>_<columns>_index
+// unless Name is given.
+func Index(columns ...string) *IndexBuilder {
+	return currentBuilder.addIndex(columns, false)
+}
+
+// UniqueIndex declares a unique index on the table, named
+// <table>_<columns>_unique unless Name is given.
+func UniqueIndex(columns ...string) *IndexBuilder {
+	return currentBuilder.addIndex(columns, true)
+}
+
+func (ib *IndexBuilder) Name(name string) *IndexBuilder {
+	ib.index.name = name
+	return ib
+}
+
+func (ib *IndexBuilder) Using(method string) *IndexBuilder {
+	Using(method)(&ib.index.options)
+	return ib
+}
+
+func (ib *IndexBuilder) Where(predicate string) *IndexBuilder {
+	Where(predicate)(&ib.index.options)
+	return ib
+}
+
+func (ib *IndexBuilder) Expression(expressions ...string) *IndexBuilder {
+	Expression(expressions...)(&ib.index.options)
+	return ib
+}
+
+func (ib *IndexBuilder) Desc(columns ...string) *IndexBuilder {
+	Desc(columns...)(&ib.index.options)
+	return ib
+}
+
+func (ib *IndexBuilder) Include(columns ...string) *IndexBuilder {
+	Include(columns...)(&ib.index.options)
+	return ib
+}
+
+func indexName(tableName string, columns []string, unique bool, o indexOptions) string {
+	parts := append([]string{}, columns...)
+	for _, expression := range o.expressions {
+		parts = append(parts, identifierFromExpression(expression))
+	}
+
+	_, table := splitTableName(tableName)
+	suffix := "index"
+	switch {
+	case unique:
+		suffix = "unique"
+	case o.method == "fulltext" || o.method == "spatial":
+		suffix = o.method
+	}
+	return fmt.Sprintf("%s_%s_%s", table, strings.Join(parts, "_"), suffix)
+}
+
+// identifierFromExpression turns lower(email) into lower_email.
+func identifierFromExpression(expression string) string {
+	var b strings.Builder
+	underscore := false
+	for i := 0; i < len(expression); i++ {
+		if c := expression[i]; isIdentifierByte(c) {
+			b.WriteByte(c)
+			underscore = false
+		} else if !underscore && b.Len() > 0 {
+			b.WriteByte('_')
+			underscore = true
+		}
+	}
+	return strings.ToLower(strings.TrimSuffix(b.String(), "_"))
+}
+
+// tableIndexes returns the indexes declared with ColumnBuilder.Index followed
+// by the table-level ones, with their names filled in.
+func (tb *TableBuilder) tableIndexes() []*tableIndex {
+	var indexes []*tableIndex
+	for _, col := range tb.columns {
+		if col.index {
+			indexes = append(indexes, &tableIndex{columns: []string{col.name}})
+		}
+	}
+	indexes = append(indexes, tb.indexes...)
+
+	for i, index := range indexes {
+		if index.name == "" {
+			named := *index
+			named.name = indexName(tb.tableName, index.columns, index.unique, index.options)
+			indexes[i] = &named
+		}
+	}
+	return indexes
+}
+
+// indexStatements renders the CREATE INDEX statements that follow the table
+// statement of Create and Modify.
+func indexStatements(dialect Dialect, tb *TableBuilder) []string {
+	var sqls []string
+	for _, index := range tb.tableIndexes() {
+		sqls = append(sqls, createIndexSQL(dialect, tb.tableName, index.columns, index.name, index.unique, index.options))
+	}
+	return sqls
+}
+
+func (tb *TableBuilder) validateIndexes() error {
+	for _, index := range tb.tableIndexes() {
+		if err := validateIndex(tb.dialect, index.columns, index.name, index.unique, index.options); err != nil {
+			return fmt.Errorf("table %s: %w", tb.tableName, err)
+		}
+	}
+	return nil
+}
+
+// validateIndex rejects index features the dialect cannot express.
+func validateIndex(dialect Dialect, columns []string, indexName string, unique bool, o indexOptions) error {
+	if len(columns) == 0 && len(o.expressions) == 0 {
+		return fmt.Errorf("index %s has no columns", indexName)
+	}
+	for _, column := range o.desc {
+		if !containsString(columns, column) {
+			return fmt.Errorf("index %s: descending column %s is not part of the index", indexName, column)
+		}
+	}
+
+	switch dialect.(type) {
+	case *PostgresDialect:
+		switch o.method {
+		case "", "btree", "hash", "gin", "gist", "spgist", "brin":
+		default:
+			return fmt.Errorf("index %s: PostgreSQL does not support %s indexes", indexName, o.method)
+		}
+	case *MySQLDialect:
+		switch o.method {
+		case "", "btree", "hash":
+		case "fulltext", "spatial":
+			if unique {
+				return fmt.Errorf("index %s: %s indexes cannot be unique", indexName, o.method)
+			}
+		default:
+			return fmt.Errorf("index %s: MySQL does not support %s indexes", indexName, o.method)
+		}
+		if o.where != "" {
+			return fmt.Errorf("index %s: MySQL does not support partial indexes", indexName)
+		}
+		if len(o.include) > 0 {
+			return fmt.Errorf("index %s: MySQL does not support INCLUDE columns", indexName)
+		}
+	case *SQLiteDialect:
+		if o.method != "" && o.method != "btree" {
+			return fmt.Errorf("index %s: SQLite does not support %s indexes", indexName, o.method)
+		}
+		if len(o.include) > 0 {
+			return fmt.Errorf("index %s: SQLite does not support INCLUDE columns", indexName)
+		}
+	}
+	return nil
+}
+
+// DropIndex drops an index of the table. Unlike the package-level DropIndex
+// it does not need to look the table up on MySQL.
+func (tb *TableBuilder) DropIndex(name string) error {
+	return tb.execute(Operation{
+		Kind:  OpDropIndex,
+		Table: tb.tableName,
+		SQL:   []string{dropIndexSQL(tb.dialect, tb.tableName, name)},
+	})
+}
+
+// dropIndexSQL drops an index. PostgreSQL indexes live in the schema of their
+// table, so the name is qualified with it.
+func dropIndexSQL(dialect Dialect, tableName, indexName string) string {
+	if _, isMySQL := dialect.(*MySQLDialect); isMySQL {
+		return fmt.Sprintf("DROP INDEX %s ON %s", dialect.QuoteIdentifier(indexName), dialect.QuoteIdentifier(tableName))
+	}
+	if schema, _ := splitTableName(tableName); schema != "" && !strings.Contains(indexName, ".") {
+		indexName = schema + "." + indexName
+	}
+	return fmt.Sprintf("DROP INDEX IF EXISTS %s", dialect.QuoteIdentifier(indexName))
+}
+
+// mysqlIndexTable finds the table an index belongs to. MySQL index names are
+// only unique per table, so a name used on several tables is an error.
+func mysqlIndexTable(q Querier, indexName string) (string, error) {
+	tables, err := queryStrings(q, `SELECT DISTINCT table_name FROM information_schema.statistics
+		WHERE table_schema = DATABASE() AND index_name = ?`, indexName)
+	if err != nil {
+		return "", fmt.Errorf("failed to look up index %s: %w", indexName, err)
+	}
+	switch len(tables) {
+	case 0:
+		return "", fmt.Errorf("index %s does not exist", indexName)
+	case 1:
+		return tables[0], nil
+	default:
+		return "", fmt.Errorf("index %s exists on tables %s, use Table(name).DropIndex", indexName, strings.Join(tables, ", "))
+	}
+}
+
+type IndexOption func(*indexOptions)
+
+type indexOptions struct {
+	concurrently bool
+	ifNotExists  bool
+	method       string
+	where        string
+	expressions  []string
+	desc         []string
+	include      []string
+}
+
+// Concurrently builds the index without blocking writes on Postgres. The
+// migration has to be NonTransactional. Other databases ignore it: MySQL
+// builds InnoDB indexes online already.
+func Concurrently() IndexOption {
+	return func(o *indexOptions) {
+		o.concurrently = true
+	}
+}
+
+// IfNotExists skips the index when one with the same name already exists.
+func IfNotExists() IndexOption {
+	return func(o *indexOptions) {
+		o.ifNotExists = true
+	}
+}
+
+// Using sets the index method: btree, hash, gin, gist, spgist or brin on
+// PostgreSQL, and btree, hash, fulltext or spatial on MySQL.
+func Using(method string) IndexOption {
+	return func(o *indexOptions) {
+		o.method = strings.ToLower(method)
+	}
+}
+
+// Where makes a partial index over the rows matching predicate. MySQL has no
+// partial indexes.
+func Where(predicate string) IndexOption {
+	return func(o *indexOptions) {
+		o.where = predicate
+	}
+}
+
+// Expression indexes the result of expressions, such as lower(email), after
+// the plain columns.
+func Expression(expressions ...string) IndexOption {
+	return func(o *indexOptions) {
+		o.expressions = append(o.expressions, expressions...)
+	}
+}
+
+// Desc sorts the given index columns in descending order.
+func Desc(columns ...string) IndexOption {
+	return func(o *indexOptions) {
+		o.desc = append(o.desc, columns...)
+	}
+}
+
+// Include adds non-key columns to the index so queries can be answered from
+// the index alone. Only PostgreSQL supports it.
+func Include(columns ...string) IndexOption {
+	return func(o *indexOptions) {
+		o.include = append(o.include, columns...)
+	}
+}
+
+func createIndex(tableName string, columns []string, indexName string, unique bool, opts []IndexOption) error {
+	s := current()
+	db, dialect := s.db, s.dialect
+
+	var o indexOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	_, isPostgres := dialect.(*PostgresDialect)
+	o.concurrently = o.concurrently && isPostgres
+
+	if err := validateIndex(dialect, columns, indexName, unique, o); err != nil {
+		return err
+	}
+
+	if o.concurrently && s.tx {
+		return fmt.Errorf("index %s: CREATE INDEX CONCURRENTLY cannot run inside a transaction, mark the migration NonTransactional", indexName)
+	}
+
+	if !s.pretending() {
+		if _, isMySQL := dialect.(*MySQLDialect); isMySQL && o.ifNotExists {
+			indexes, err := dialect.GetIndexes(db, tableName)
+			if err != nil {
+				return err
+			}
+			for _, index := range indexes {
+				if strings.EqualFold(index.Name, indexName) {
+					return nil
+				}
+			}
+		}
+
+		// A concurrent build that failed earlier leaves an INVALID index
+		// behind, which would make this one fail or be skipped.
+		if o.concurrently {
+			if err := dropInvalidIndex(db, dialect, tableName, indexName); err != nil {
+				return err
+			}
+		}
+	}
+
+	err := s.execute(Operation{
+		Kind:    OpCreateIndex,
+		Table:   tableName,
+		SQL:     []string{createIndexSQL(dialect, tableName, columns, indexName, unique, o)},
+		indexes: []string{indexName},
+	})
+	if err != nil && o.concurrently {
+		if cleanupErr := dropInvalidIndex(db, dialect, tableName, indexName); cleanupErr != nil {
+			return errors.Join(err, fmt.Errorf("failed to drop invalid index %s: %w", indexName, cleanupErr))
+		}
+	}
+	return err
+}
+
+func createIndexSQL(dialect Dialect, tableName string, columns []string, indexName string, unique bool, o indexOptions) string {
+	_, isMySQL := dialect.(*MySQLDialect)
+
+	query := "CREATE "
+	if unique {
+		query += "UNIQUE "
+	} else if isMySQL && (o.method == "fulltext" || o.method == "spatial") {
+		query += strings.ToUpper(o.method) + " "
+	}
+	query += "INDEX "
+
+	switch dialect.(type) {
+	case *PostgresDialect:
+		if o.concurrently {
+			query += "CONCURRENTLY "
+		}
+		if o.ifNotExists {
+			query += "IF NOT EXISTS "
+		}
+	case *SQLiteDialect:
+		if o.ifNotExists {
+			query += "IF NOT EXISTS "
+		}
+	}
+
+	var parts []string
+	for _, column := range columns {
+		part := dialect.QuoteIdentifier(column)
+		if containsString(o.desc, column) {
+			part += " DESC"
+		}
+		parts = append(parts, part)
+	}
+	for _, expression := range o.expressions {
+		parts = append(parts, "("+expression+")")
+	}
+
+	query += fmt.Sprintf("%s ON %s", dialect.QuoteIdentifier(indexName), dialect.QuoteIdentifier(tableName))
+	if _, isPostgres := dialect.(*PostgresDialect); isPostgres && o.method != "" {
+		query += " USING " + strings.ToUpper(o.method)
+	}
+	query += fmt.Sprintf(" (%s)", strings.Join(parts, ", "))
+	if isMySQL && (o.method == "btree" || o.method == "hash") {
+		query += " USING " + strings.ToUpper(o.method)
+	}
+	if len(o.include) > 0 {
+		query += fmt.Sprintf(" INCLUDE (%s)", quoteColumns(dialect, o.include))
+	}
+	if o.where != "" {
+		query += " WHERE " + o.where
+	}
+	return query
+}
+
+func dropInvalidIndex(q Querier, dialect Dialect, tableName, indexName string) error {
+	schema, _ := splitTableName(tableName)
+
+	var valid bool
+	err := q.QueryRow(`
+		SELECT i.indisvalid
+		FROM pg_index i
+		JOIN pg_class c ON c.oid = i.indexrelid
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		WHERE c.relname = $1 AND n.nspname = COALESCE(NULLIF($2, ''), current_schema())`, indexName, schema).Scan(&valid)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && valid) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if schema != "" {
+		indexName = schema + "." + indexName
+	}
+	_, err = q.Exec(fmt.Sprintf("DROP INDEX CONCURRENTLY IF EXISTS %s", dialect.QuoteIdentifier(indexName)))
+	return err
+}
@@ -0,0 +1,250 @@
+package olympian
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCreateIndexSQL(t *testing.T) {
+	tests := []struct {
+		dialect  Dialect
+		unique   bool
+		opts     indexOptions
+		expected string
+	}{
+		{&PostgresDialect{}, false, indexOptions{}, "CREATE INDEX idx_users_email ON users (email)"},
+		{&PostgresDialect{}, true, indexOptions{concurrently: true, ifNotExists: true}, "CREATE UNIQUE INDEX CONCURRENTLY IF NOT EXISTS idx_users_email ON users (email)"},
+		{&SQLiteDialect{}, false, indexOptions{ifNotExists: true}, "CREATE INDEX IF NOT EXISTS idx_users_email ON users (email)"},
+		{&MySQLDialect{}, false, indexOptions{ifNotExists: true}, "CREATE INDEX idx_users_email ON users (email)"},
+	}
+
+	for _, tt := range tests {
+		result := createIndexSQL(tt.dialect, "users", []string{"email"}, "idx_users_email", tt.unique, tt.opts)
+		if result != tt.expected {
+			t.Errorf("Expected %s, got %s", tt.expected, result)
+		}
+	}
+}
+
+func TestCreateIndexIfNotExists(t *testing.T) {
+	db := setupTestDB(t)
+	defer func() { _ = db.Close() }()
+
+	SetDB(db, &SQLiteDialect{})
+
+	if err := Table("users").Create(func() {
+		Uuid("id").Primary()
+		String("email")
+	}); err != nil {
+		t.Fatalf("Failed to create table: %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := CreateIndex("users", []string{"email"}, "idx_users_email", IfNotExists(), Concurrently()); err != nil {
+			t.Fatalf("Failed to create index: %v", err)
+		}
+	}
+
+	if err := CreateIndex("users", []string{"email"}, "idx_users_email"); err == nil {
+		t.Error("Expected creating a duplicate index without IfNotExists to fail")
+	}
+}
+
+func TestConcurrentIndexRequiresNonTransactional(t *testing.T) {
+	db := setupTestDB(t)
+	defer func() { _ = db.Close() }()
+
+	migrator := NewMigrator(db, &SQLiteDialect{})
+	if err := migrator.Init(); err != nil {
+		t.Fatalf("Failed to initialize migrator: %v", err)
+	}
+
+	// The transaction check only depends on the dialect, so swap in Postgres
+	// while the migration body runs.
+	migrations := []Migration{
+		{
+			Name: "1_index_users_email",
+			Up: func() error {
+				_, dialect := conn()
+				SetDB(db, &PostgresDialect{})
+				defer SetDB(db, dialect)
+				return CreateIndex("users", []string{"email"}, "idx_users_email", Concurrently())
+			},
+			Down: func() error { return nil },
+		},
+	}
+
+	err := migrator.Migrate(migrations)
+	if err == nil || !strings.Contains(err.Error(), "NonTransactional") {
+		t.Errorf("Expected an error asking for NonTransactional, got %v", err)
+	}
+}
+
+func TestInlineIndexSQL(t *testing.T) {
+	tb := &TableBuilder{tableName: "posts"}
+	currentBuilder = tb
+	Integer("id").Primary()
+	Integer("author_id").Index()
+	String("slug")
+	UniqueIndex("author_id", "slug")
+	Index("slug").Name("posts_slug_lookup")
+	currentBuilder = nil
+
+	sqls, err := (&PostgresDialect{}).BuildCreateTable(tb)
+	if err != nil {
+		t.Fatalf("Failed to build SQL: %v", err)
+	}
+	expected := []string{
+		"CREATE INDEX posts_author_id_index ON posts (author_id)",
+		"CREATE UNIQUE INDEX posts_author_id_slug_unique ON posts (author_id, slug)",
+		"CREATE INDEX posts_slug_lookup ON posts (slug)",
+	}
+	if len(sqls) != 4 || strings.Join(sqls[1:], "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Expected the table followed by %v, got %v", expected, sqls)
+	}
+
+	modify := &TableBuilder{tableName: "posts", indexes: []*tableIndex{{columns: []string{"slug"}}}}
+	sqls, err = (&MySQLDialect{}).BuildModifyTable(modify)
+	if err != nil {
+		t.Fatalf("Failed to build SQL: %v", err)
+	}
+	if len(sqls) != 1 || sqls[0] != "CREATE INDEX posts_slug_index ON posts (slug)" {
+		t.Errorf("Unexpected SQL: %v", sqls)
+	}
+}
+
+func TestDropIndexSQL(t *testing.T) {
+	if result := dropIndexSQL(&MySQLDialect{}, "posts", "posts_slug_index"); result != "DROP INDEX posts_slug_index ON posts" {
+		t.Errorf("Unexpected MySQL SQL: %s", result)
+	}
+	if result := dropIndexSQL(&PostgresDialect{}, "posts", "posts_slug_index"); result != "DROP INDEX IF EXISTS posts_slug_index" {
+		t.Errorf("Unexpected Postgres SQL: %s", result)
+	}
+}
+
+func TestInlineIndexes(t *testing.T) {
+	db := setupTestDB(t)
+	defer func() { _ = db.Close() }()
+
+	SetDB(db, &SQLiteDialect{})
+
+	if err := Table("posts").Create(func() {
+		Integer("id").Primary()
+		Integer("author_id").Index()
+	}); err != nil {
+		t.Fatalf("Failed to create table: %v", err)
+	}
+	if err := Table("posts").Modify(func() {
+		String("slug").Nullable()
+		UniqueIndex("author_id", "slug")
+	}); err != nil {
+		t.Fatalf("Failed to modify table: %v", err)
+	}
+
+	indexes, err := GetIndexes("posts")
+	if err != nil {
+		t.Fatalf("Failed to read indexes: %v", err)
+	}
+	names := map[string]bool{}
+	for _, index := range indexes {
+		names[index.Name] = true
+	}
+	if !names["posts_author_id_index"] || !names["posts_author_id_slug_unique"] {
+		t.Errorf("Expected the declared indexes, got %+v", indexes)
+	}
+
+	if err := Table("posts").DropIndex("posts_author_id_slug_unique"); err != nil {
+		t.Fatalf("Failed to drop index: %v", err)
+	}
+	if ok, _ := HasIndex("posts", "posts_author_id_slug_unique"); ok {
+		t.Error("Expected the index to be dropped")
+	}
+}
+
+func TestAdvancedIndexSQL(t *testing.T) {
+	tests := []struct {
+		dialect  Dialect
+		columns  []string
+		opts     []IndexOption
+		expected string
+	}{
+		{&PostgresDialect{}, []string{"document"}, []IndexOption{Using("gin")}, "CREATE INDEX idx ON posts USING GIN (document)"},
+		{&PostgresDialect{}, []string{"email"}, []IndexOption{Where("deleted_at IS NULL"), Include("name")}, "CREATE INDEX idx ON posts (email) INCLUDE (name) WHERE deleted_at IS NULL"},
+		{&PostgresDialect{}, nil, []IndexOption{Expression("lower(email)")}, "CREATE INDEX idx ON posts ((lower(email)))"},
+		{&MySQLDialect{}, []string{"title", "body"}, []IndexOption{Using("fulltext")}, "CREATE FULLTEXT INDEX idx ON posts (title, body)"},
+		{&MySQLDialect{}, []string{"author_id", "created_at"}, []IndexOption{Desc("created_at"), Using("btree")}, "CREATE INDEX idx ON posts (author_id, created_at DESC) USING BTREE"},
+		{&SQLiteDialect{}, []string{"email"}, []IndexOption{Where("deleted_at IS NULL")}, "CREATE INDEX idx ON posts (email) WHERE deleted_at IS NULL"},
+	}
+
+	for _, tt := range tests {
+		var o indexOptions
+		for _, opt := range tt.opts {
+			opt(&o)
+		}
+		if err := validateIndex(tt.dialect, tt.columns, "idx", false, o); err != nil {
+			t.Errorf("Unexpected validation error: %v", err)
+		}
+		if result := createIndexSQL(tt.dialect, "posts", tt.columns, "idx", false, o); result != tt.expected {
+			t.Errorf("Expected %s, got %s", tt.expected, result)
+		}
+	}
+}
+
+func TestUnsupportedIndexFeatures(t *testing.T) {
+	tests := []struct {
+		dialect Dialect
+		unique  bool
+		opt     IndexOption
+	}{
+		{&MySQLDialect{}, false, Where("deleted_at IS NULL")},
+		{&MySQLDialect{}, false, Include("name")},
+		{&MySQLDialect{}, true, Using("fulltext")},
+		{&MySQLDialect{}, false, Using("gin")},
+		{&PostgresDialect{}, false, Using("fulltext")},
+		{&SQLiteDialect{}, false, Using("gin")},
+		{&SQLiteDialect{}, false, Include("name")},
+		{&SQLiteDialect{}, false, Desc("name")},
+	}
+
+	for _, tt := range tests {
+		var o indexOptions
+		tt.opt(&o)
+		if err := validateIndex(tt.dialect, []string{"email"}, "idx", tt.unique, o); err == nil {
+			t.Errorf("Expected an error for %+v on %T", o, tt.dialect)
+		}
+	}
+}
+
+func TestPartialAndExpressionIndexes(t *testing.T) {
+	db := setupTestDB(t)
+	defer func() { _ = db.Close() }()
+
+	SetDB(db, &SQLiteDialect{})
+
+	if err := Table("users").Create(func() {
+		Integer("id").Primary()
+		String("email")
+		Timestamp("deleted_at").Nullable()
+		UniqueIndex("email").Where("deleted_at IS NULL")
+		Index().Expression("lower(email)")
+	}); err != nil {
+		t.Fatalf("Failed to create table: %v", err)
+	}
+
+	if _, err := db.Exec("INSERT INTO users (id, email, deleted_at) VALUES (1, 'a@b.c', '2024-01-01'), (2, 'a@b.c', NULL)"); err != nil {
+		t.Errorf("Expected the partial unique index to ignore deleted rows: %v", err)
+	}
+	if _, err := db.Exec("INSERT INTO users (id, email) VALUES (3, 'a@b.c')"); err == nil {
+		t.Error("Expected the partial unique index to reject a duplicate live email")
+	}
+	if ok, _ := HasIndex("users", "users_lower_email_index"); !ok {
+		t.Error("Expected the expression index users_lower_email_index")
+	}
+
+	if err := Table("posts").Create(func() {
+		Integer("id").Primary()
+		Index("id").Using("gin")
+	}); err == nil {
+		t.Error("Expected an error for a GIN index on SQLite")
+	}
+}
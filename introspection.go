@@ -0,0 +1,657 @@
+package olympian
+
+import (
+	"database/sql"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+type Querier interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+type TableInfo struct {
+	Name        string
+	Columns     []ColumnInfo
+	Indexes     []IndexInfo
+	ForeignKeys []ForeignKeyInfo
+}
+
+type ColumnInfo struct {
+	Name          string
+	Type          string
+	Nullable      bool
+	Default       *string
+	Primary       bool
+	AutoIncrement bool
+	Comment       string
+	Collation     string
+}
+
+type IndexInfo struct {
+	Name    string
+	Columns []string
+	Unique  bool
+	Primary bool
+}
+
+type ForeignKeyInfo struct {
+	Name       string
+	Columns    []string
+	RefTable   string
+	RefColumns []string
+	OnDelete   string
+	OnUpdate   string
+}
+
+// ViewInfo describes a view. Definition is the query as the database stores
+// it.
+type ViewInfo struct {
+	Name         string
+	Definition   string
+	Materialized bool
+}
+
+func (t *TableInfo) Column(name string) (ColumnInfo, bool) {
+	for _, col := range t.Columns {
+		if strings.EqualFold(col.Name, name) {
+			return col, true
+		}
+	}
+	return ColumnInfo{}, false
+}
+
+func GetTables() ([]string, error) {
+	db, dialect := conn()
+	return dialect.GetTables(db)
+}
+
+func HasTable(tableName string) (bool, error) {
+	tables, err := GetTables()
+	if err != nil {
+		return false, err
+	}
+	for _, table := range tables {
+		if strings.EqualFold(table, tableName) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func GetColumns(tableName string) ([]ColumnInfo, error) {
+	db, dialect := conn()
+	return dialect.GetColumns(db, tableName)
+}
+
+func HasColumn(tableName, columnName string) (bool, error) {
+	columns, err := GetColumns(tableName)
+	if err != nil {
+		return false, err
+	}
+	for _, col := range columns {
+		if strings.EqualFold(col.Name, columnName) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func GetIndexes(tableName string) ([]IndexInfo, error) {
+	db, dialect := conn()
+	return dialect.GetIndexes(db, tableName)
+}
+
+func HasIndex(tableName, indexName string) (bool, error) {
+	indexes, err := GetIndexes(tableName)
+	if err != nil {
+		return false, err
+	}
+	for _, index := range indexes {
+		if strings.EqualFold(index.Name, indexName) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func GetForeignKeys(tableName string) ([]ForeignKeyInfo, error) {
+	db, dialect := conn()
+	return dialect.GetForeignKeys(db, tableName)
+}
+
+func GetViews() ([]ViewInfo, error) {
+	db, dialect := conn()
+	return dialect.GetViews(db)
+}
+
+func HasView(viewName string) (bool, error) {
+	views, err := GetViews()
+	if err != nil {
+		return false, err
+	}
+	for _, view := range views {
+		if strings.EqualFold(view.Name, viewName) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func DescribeTable(tableName string) (*TableInfo, error) {
+	db, dialect := conn()
+	return describeTable(db, dialect, tableName)
+}
+
+func describeTable(q Querier, dialect Dialect, tableName string) (*TableInfo, error) {
+	columns, err := dialect.GetColumns(q, tableName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get columns of %s: %w", tableName, err)
+	}
+	indexes, err := dialect.GetIndexes(q, tableName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get indexes of %s: %w", tableName, err)
+	}
+	foreignKeys, err := dialect.GetForeignKeys(q, tableName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get foreign keys of %s: %w", tableName, err)
+	}
+	return &TableInfo{
+		Name:        tableName,
+		Columns:     columns,
+		Indexes:     indexes,
+		ForeignKeys: foreignKeys,
+	}, nil
+}
+
+// GetTables returns the tables of every schema on the search path. Tables
+// outside the current schema are qualified with their schema.
+func (d *PostgresDialect) GetTables(q Querier) ([]string, error) {
+	return queryStrings(q, `SELECT CASE WHEN table_schema = current_schema() THEN table_name
+			ELSE table_schema || '.' || table_name END
+		FROM information_schema.tables
+		WHERE table_schema = ANY(current_schemas(false)) AND table_type = 'BASE TABLE'
+		ORDER BY table_schema <> current_schema(), 1`)
+}
+
+// GetViews returns the views and materialized views of every schema on the
+// search path, qualified like GetTables.
+func (d *PostgresDialect) GetViews(q Querier) ([]ViewInfo, error) {
+	rows, err := q.Query(`SELECT CASE WHEN n.nspname = current_schema() THEN c.relname
+			ELSE n.nspname || '.' || c.relname END,
+			pg_get_viewdef(c.oid, true), c.relkind = 'm'
+		FROM pg_class c
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		WHERE c.relkind IN ('v', 'm') AND n.nspname = ANY(current_schemas(false))
+		ORDER BY n.nspname <> current_schema(), 1`)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var views []ViewInfo
+	for rows.Next() {
+		var view ViewInfo
+		if err := rows.Scan(&view.Name, &view.Definition, &view.Materialized); err != nil {
+			return nil, err
+		}
+		view.Definition = strings.TrimSuffix(strings.TrimSpace(view.Definition), ";")
+		views = append(views, view)
+	}
+	return views, rows.Err()
+}
+
+// GetColumns, GetIndexes and GetForeignKeys accept schema-qualified table
+// names and look unqualified ones up in the current schema.
+func (d *PostgresDialect) GetColumns(q Querier, tableName string) ([]ColumnInfo, error) {
+	schema, table := splitTableName(tableName)
+	primary, err := queryStrings(q, `SELECT kcu.column_name
+		FROM information_schema.table_constraints tc
+		JOIN information_schema.key_column_usage kcu
+			ON kcu.constraint_name = tc.constraint_name
+			AND kcu.table_schema = tc.table_schema
+			AND kcu.table_name = tc.table_name
+		WHERE tc.constraint_type = 'PRIMARY KEY'
+			AND tc.table_schema = COALESCE(NULLIF($2, ''), current_schema()) AND tc.table_name = $1`, table, schema)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := q.Query(`SELECT column_name, data_type, character_maximum_length,
+			numeric_precision, numeric_scale, is_nullable, column_default,
+			COALESCE(col_description(format('%I.%I', table_schema, table_name)::regclass, ordinal_position), ''),
+			COALESCE(collation_name, '')
+		FROM information_schema.columns
+		WHERE table_schema = COALESCE(NULLIF($2, ''), current_schema()) AND table_name = $1
+		ORDER BY ordinal_position`, table, schema)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var columns []ColumnInfo
+	for rows.Next() {
+		var col ColumnInfo
+		var length, precision, scale sql.NullInt64
+		var nullable string
+		var dflt sql.NullString
+		if err := rows.Scan(&col.Name, &col.Type, &length, &precision, &scale, &nullable, &dflt, &col.Comment, &col.Collation); err != nil {
+			return nil, err
+		}
+		switch {
+		case length.Valid:
+			col.Type = fmt.Sprintf("%s(%d)", col.Type, length.Int64)
+		case col.Type == "numeric" && precision.Valid:
+			col.Type = fmt.Sprintf("numeric(%d,%d)", precision.Int64, scale.Int64)
+		}
+		col.Nullable = nullable == "YES"
+		col.Default = nullString(dflt)
+		col.Primary = containsString(primary, col.Name)
+		columns = append(columns, col)
+	}
+	return columns, rows.Err()
+}
+
+func (d *PostgresDialect) GetIndexes(q Querier, tableName string) ([]IndexInfo, error) {
+	schema, table := splitTableName(tableName)
+	rows, err := q.Query(`SELECT i.relname, ix.indisunique, ix.indisprimary,
+			COALESCE(a.attname, pg_get_indexdef(ix.indexrelid, k.ord::int, true))
+		FROM pg_index ix
+		JOIN pg_class t ON t.oid = ix.indrelid
+		JOIN pg_class i ON i.oid = ix.indexrelid
+		JOIN pg_namespace n ON n.oid = t.relnamespace
+		CROSS JOIN LATERAL unnest(ix.indkey) WITH ORDINALITY AS k(attnum, ord)
+		LEFT JOIN pg_attribute a ON a.attrelid = t.oid AND a.attnum = k.attnum
+		WHERE n.nspname = COALESCE(NULLIF($2, ''), current_schema()) AND t.relname = $1
+		ORDER BY i.relname, k.ord`, table, schema)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var indexes []IndexInfo
+	for rows.Next() {
+		var name, column string
+		var unique, primary bool
+		if err := rows.Scan(&name, &unique, &primary, &column); err != nil {
+			return nil, err
+		}
+		if n := len(indexes); n > 0 && indexes[n-1].Name == name {
+			indexes[n-1].Columns = append(indexes[n-1].Columns, column)
+			continue
+		}
+		indexes = append(indexes, IndexInfo{
+			Name:    name,
+			Columns: []string{column},
+			Unique:  unique,
+			Primary: primary,
+		})
+	}
+	return indexes, rows.Err()
+}
+
+// GetForeignKeys qualifies referenced tables outside the current schema.
+func (d *PostgresDialect) GetForeignKeys(q Querier, tableName string) ([]ForeignKeyInfo, error) {
+	schema, table := splitTableName(tableName)
+	rows, err := q.Query(`SELECT kcu.constraint_name, kcu.column_name,
+			CASE WHEN ref.table_schema = current_schema() THEN ref.table_name
+				ELSE ref.table_schema || '.' || ref.table_name END,
+			ref.column_name, rc.delete_rule, rc.update_rule
+		FROM information_schema.referential_constraints rc
+		JOIN information_schema.key_column_usage kcu
+			ON kcu.constraint_name = rc.constraint_name
+			AND kcu.constraint_schema = rc.constraint_schema
+		JOIN information_schema.key_column_usage ref
+			ON ref.constraint_name = rc.unique_constraint_name
+			AND ref.constraint_schema = rc.unique_constraint_schema
+			AND ref.ordinal_position = kcu.position_in_unique_constraint
+		WHERE kcu.table_schema = COALESCE(NULLIF($2, ''), current_schema()) AND kcu.table_name = $1
+		ORDER BY kcu.constraint_name, kcu.ordinal_position`, table, schema)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	return scanForeignKeys(rows)
+}
+
+func (d *MySQLDialect) GetTables(q Querier) ([]string, error) {
+	return queryStrings(q, `SELECT TABLE_NAME FROM information_schema.TABLES
+		WHERE TABLE_SCHEMA = DATABASE() AND TABLE_TYPE = 'BASE TABLE'
+		ORDER BY TABLE_NAME`)
+}
+
+// GetViews returns the views of the current database. MySQL stores view
+// queries with every identifier qualified by the database, which is removed
+// so the definitions compare across databases.
+func (d *MySQLDialect) GetViews(q Querier) ([]ViewInfo, error) {
+	rows, err := q.Query(`SELECT TABLE_NAME, VIEW_DEFINITION, TABLE_SCHEMA FROM information_schema.VIEWS
+		WHERE TABLE_SCHEMA = DATABASE()
+		ORDER BY TABLE_NAME`)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var views []ViewInfo
+	for rows.Next() {
+		var view ViewInfo
+		var schema string
+		if err := rows.Scan(&view.Name, &view.Definition, &schema); err != nil {
+			return nil, err
+		}
+		view.Definition = unqualifyMySQLView(view.Definition, schema)
+		views = append(views, view)
+	}
+	return views, rows.Err()
+}
+
+func (d *MySQLDialect) GetColumns(q Querier, tableName string) ([]ColumnInfo, error) {
+	rows, err := q.Query(`SELECT COLUMN_NAME, COLUMN_TYPE, IS_NULLABLE, COLUMN_DEFAULT, COLUMN_KEY, EXTRA, COLUMN_COMMENT,
+			COALESCE(COLLATION_NAME, '')
+		FROM information_schema.COLUMNS
+		WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = ?
+		ORDER BY ORDINAL_POSITION`, tableName)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var columns []ColumnInfo
+	for rows.Next() {
+		var col ColumnInfo
+		var nullable, key, extra string
+		var dflt sql.NullString
+		if err := rows.Scan(&col.Name, &col.Type, &nullable, &dflt, &key, &extra, &col.Comment, &col.Collation); err != nil {
+			return nil, err
+		}
+		col.Nullable = nullable == "YES"
+		if dflt.Valid {
+			value := mysqlColumnDefault(dflt.String, col.Type, extra)
+			col.Default = &value
+		}
+		col.Primary = key == "PRI"
+		col.AutoIncrement = strings.Contains(strings.ToLower(extra), "auto_increment")
+		columns = append(columns, col)
+	}
+	return columns, rows.Err()
+}
+
+var mysqlNumericType = regexp.MustCompile(`(?i)^(tinyint|smallint|mediumint|int|integer|bigint|decimal|numeric|float|double|real|bit|bool|boolean)\b`)
+
+// mysqlColumnDefault turns a COLUMN_DEFAULT, which holds string literals
+// without quotes, into SQL as the other dialects return it. Expressions are
+// marked DEFAULT_GENERATED and need parentheses when restated, except
+// CURRENT_TIMESTAMP, which MySQL 5.7 does not mark.
+func mysqlColumnDefault(value, columnType, extra string) string {
+	switch {
+	case strings.HasPrefix(strings.ToUpper(value), "CURRENT_TIMESTAMP"):
+		return value
+	case strings.Contains(strings.ToUpper(extra), "DEFAULT_GENERATED"):
+		return "(" + value + ")"
+	case mysqlNumericType.MatchString(columnType):
+		return value
+	}
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
+
+func (d *MySQLDialect) GetIndexes(q Querier, tableName string) ([]IndexInfo, error) {
+	var expressions int
+	err := q.QueryRow(`SELECT COUNT(*) FROM information_schema.COLUMNS
+		WHERE TABLE_SCHEMA = 'information_schema' AND TABLE_NAME = 'STATISTICS'
+			AND COLUMN_NAME = 'EXPRESSION'`).Scan(&expressions)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := q.Query(mysqlIndexQuery(expressions > 0), tableName)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var indexes []IndexInfo
+	for rows.Next() {
+		var name, column string
+		var nonUnique int
+		if err := rows.Scan(&name, &column, &nonUnique); err != nil {
+			return nil, err
+		}
+		if n := len(indexes); n > 0 && indexes[n-1].Name == name {
+			indexes[n-1].Columns = append(indexes[n-1].Columns, column)
+			continue
+		}
+		indexes = append(indexes, IndexInfo{
+			Name:    name,
+			Columns: []string{column},
+			Unique:  nonUnique == 0,
+			Primary: name == "PRIMARY",
+		})
+	}
+	return indexes, rows.Err()
+}
+
+// mysqlIndexQuery lists the index columns of a table. Functional index parts
+// have no COLUMN_NAME, so their EXPRESSION is used instead where it exists;
+// MySQL before 8.0.13 and MariaDB have neither the column nor such indexes.
+func mysqlIndexQuery(hasExpression bool) string {
+	column := "COLUMN_NAME"
+	if hasExpression {
+		column = "COALESCE(COLUMN_NAME, EXPRESSION)"
+	}
+	return `SELECT INDEX_NAME, ` + column + `, NON_UNIQUE
+		FROM information_schema.STATISTICS
+		WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = ?
+		ORDER BY INDEX_NAME, SEQ_IN_INDEX`
+}
+
+func (d *MySQLDialect) GetForeignKeys(q Querier, tableName string) ([]ForeignKeyInfo, error) {
+	rows, err := q.Query(`SELECT k.CONSTRAINT_NAME, k.COLUMN_NAME, k.REFERENCED_TABLE_NAME,
+			k.REFERENCED_COLUMN_NAME, r.DELETE_RULE, r.UPDATE_RULE
+		FROM information_schema.KEY_COLUMN_USAGE k
+		JOIN information_schema.REFERENTIAL_CONSTRAINTS r
+			ON r.CONSTRAINT_SCHEMA = k.CONSTRAINT_SCHEMA
+			AND r.CONSTRAINT_NAME = k.CONSTRAINT_NAME
+		WHERE k.TABLE_SCHEMA = DATABASE() AND k.TABLE_NAME = ?
+			AND k.REFERENCED_TABLE_NAME IS NOT NULL
+		ORDER BY k.CONSTRAINT_NAME, k.ORDINAL_POSITION`, tableName)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	return scanForeignKeys(rows)
+}
+
+func (d *SQLiteDialect) GetTables(q Querier) ([]string, error) {
+	return queryStrings(q, `SELECT name FROM sqlite_master
+		WHERE type = 'table' AND name NOT LIKE 'sqlite_%'
+		ORDER BY name`)
+}
+
+// GetViews returns the query of each view, taken from its CREATE VIEW
+// statement.
+func (d *SQLiteDialect) GetViews(q Querier) ([]ViewInfo, error) {
+	rows, err := q.Query(`SELECT name, sql FROM sqlite_master WHERE type = 'view' ORDER BY name`)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var views []ViewInfo
+	for rows.Next() {
+		var view ViewInfo
+		if err := rows.Scan(&view.Name, &view.Definition); err != nil {
+			return nil, err
+		}
+		if i := topLevelKeyword(view.Definition, "AS"); i >= 0 {
+			view.Definition = strings.TrimSpace(view.Definition[i+len("AS"):])
+		}
+		views = append(views, view)
+	}
+	return views, rows.Err()
+}
+
+func (d *SQLiteDialect) GetColumns(q Querier, tableName string) ([]ColumnInfo, error) {
+	// table_xinfo also lists generated columns, which table_info hides.
+	rows, err := q.Query(`SELECT name, type, "notnull", dflt_value, pk FROM pragma_table_xinfo(?) WHERE hidden <> 1`, tableName)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var columns []ColumnInfo
+	for rows.Next() {
+		var col ColumnInfo
+		var notNull, pk int
+		var dflt sql.NullString
+		if err := rows.Scan(&col.Name, &col.Type, &notNull, &dflt, &pk); err != nil {
+			return nil, err
+		}
+		col.Nullable = notNull == 0 && pk == 0
+		col.Default = nullString(dflt)
+		col.Primary = pk > 0
+		columns = append(columns, col)
+	}
+	return columns, rows.Err()
+}
+
+func (d *SQLiteDialect) GetIndexes(q Querier, tableName string) ([]IndexInfo, error) {
+	rows, err := q.Query(`SELECT name, "unique", origin FROM pragma_index_list(?) ORDER BY name`, tableName)
+	if err != nil {
+		return nil, err
+	}
+
+	var indexes []IndexInfo
+	for rows.Next() {
+		var index IndexInfo
+		var unique int
+		var origin string
+		if err := rows.Scan(&index.Name, &unique, &origin); err != nil {
+			_ = rows.Close()
+			return nil, err
+		}
+		index.Unique = unique == 1
+		index.Primary = origin == "pk"
+		indexes = append(indexes, index)
+	}
+	if err := rows.Err(); err != nil {
+		_ = rows.Close()
+		return nil, err
+	}
+	_ = rows.Close()
+
+	// Index columns are read once the list is closed, as an in-memory
+	// database only lives on a single connection.
+	for i := range indexes {
+		columns, err := queryStrings(q, `SELECT COALESCE(name, '<expression>') FROM pragma_index_info(?) ORDER BY seqno`, indexes[i].Name)
+		if err != nil {
+			return nil, err
+		}
+		indexes[i].Columns = columns
+	}
+	return indexes, nil
+}
+
+func (d *SQLiteDialect) GetForeignKeys(q Querier, tableName string) ([]ForeignKeyInfo, error) {
+	rows, err := q.Query(`SELECT id, "from", "table", COALESCE("to", ''), on_delete, on_update
+		FROM pragma_foreign_key_list(?)
+		ORDER BY id, seq`, tableName)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var foreignKeys []ForeignKeyInfo
+	lastID := -1
+	for rows.Next() {
+		var id int
+		var from, refTable, to, onDelete, onUpdate string
+		if err := rows.Scan(&id, &from, &refTable, &to, &onDelete, &onUpdate); err != nil {
+			return nil, err
+		}
+		if id == lastID {
+			fk := &foreignKeys[len(foreignKeys)-1]
+			fk.Columns = append(fk.Columns, from)
+			fk.RefColumns = append(fk.RefColumns, to)
+			continue
+		}
+		lastID = id
+		foreignKeys = append(foreignKeys, ForeignKeyInfo{
+			Columns:    []string{from},
+			RefTable:   refTable,
+			RefColumns: []string{to},
+			OnDelete:   onDelete,
+			OnUpdate:   onUpdate,
+		})
+	}
+	return foreignKeys, rows.Err()
+}
+
+func scanForeignKeys(rows *sql.Rows) ([]ForeignKeyInfo, error) {
+	var foreignKeys []ForeignKeyInfo
+	for rows.Next() {
+		var name, column, refTable, refColumn, onDelete, onUpdate string
+		if err := rows.Scan(&name, &column, &refTable, &refColumn, &onDelete, &onUpdate); err != nil {
+			return nil, err
+		}
+		if n := len(foreignKeys); n > 0 && foreignKeys[n-1].Name == name {
+			foreignKeys[n-1].Columns = append(foreignKeys[n-1].Columns, column)
+			foreignKeys[n-1].RefColumns = append(foreignKeys[n-1].RefColumns, refColumn)
+			continue
+		}
+		foreignKeys = append(foreignKeys, ForeignKeyInfo{
+			Name:       name,
+			Columns:    []string{column},
+			RefTable:   refTable,
+			RefColumns: []string{refColumn},
+			OnDelete:   onDelete,
+			OnUpdate:   onUpdate,
+		})
+	}
+	return foreignKeys, rows.Err()
+}
+
+func queryStrings(q Querier, query string, args ...interface{}) ([]string, error) {
+	rows, err := q.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var values []string
+	for rows.Next() {
+		var value string
+		if err := rows.Scan(&value); err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	return values, rows.Err()
+}
+
+// unqualifyMySQLView removes the `schema`. prefix MySQL puts in front of the
+// tables and columns of a stored view query.
+func unqualifyMySQLView(definition, schema string) string {
+	prefix := "`" + strings.ReplaceAll(schema, "`", "``") + "`."
+	return strings.ReplaceAll(definition, prefix, "")
+}
+
+func nullString(s sql.NullString) *string {
+	if !s.Valid {
+		return nil
+	}
+	return &s.String
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,149 @@
+package olympian
+
+import (
+	"strings"
+	"testing"
+)
+
+func setupIntrospectionDB(t *testing.T) {
+	db := setupTestDB(t)
+	t.Cleanup(func() { _ = db.Close() })
+
+	SetDB(db, &SQLiteDialect{})
+
+	err := Table("businesses").Create(func() {
+		Uuid("id").Primary()
+		String("name")
+	})
+	if err != nil {
+		t.Fatalf("Failed to create businesses table: %v", err)
+	}
+
+	err = Table("users").Create(func() {
+		Uuid("id").Primary()
+		String("business_id")
+		String("email").Unique()
+		Integer("age").Nullable()
+		Boolean("active").Default(true)
+		Foreign("business_id").References("id").On("businesses").OnDelete("cascade")
+	})
+	if err != nil {
+		t.Fatalf("Failed to create users table: %v", err)
+	}
+
+	if err := CreateIndex("users", []string{"business_id", "age"}, "idx_users_business_age"); err != nil {
+		t.Fatalf("Failed to create index: %v", err)
+	}
+}
+
+func TestHasTableAndColumn(t *testing.T) {
+	setupIntrospectionDB(t)
+
+	tables, err := GetTables()
+	if err != nil {
+		t.Fatalf("Failed to get tables: %v", err)
+	}
+	if len(tables) != 2 || tables[0] != "businesses" || tables[1] != "users" {
+		t.Errorf("Expected [businesses users], got %v", tables)
+	}
+
+	if ok, err := HasTable("users"); err != nil || !ok {
+		t.Errorf("Expected users table to exist, got %v, %v", ok, err)
+	}
+	if ok, err := HasTable("orders"); err != nil || ok {
+		t.Errorf("Expected orders table not to exist, got %v, %v", ok, err)
+	}
+	if ok, err := HasColumn("users", "email"); err != nil || !ok {
+		t.Errorf("Expected users.email to exist, got %v, %v", ok, err)
+	}
+	if ok, err := HasColumn("users", "phone"); err != nil || ok {
+		t.Errorf("Expected users.phone not to exist, got %v, %v", ok, err)
+	}
+}
+
+func TestGetColumns(t *testing.T) {
+	setupIntrospectionDB(t)
+
+	table, err := DescribeTable("users")
+	if err != nil {
+		t.Fatalf("Failed to describe table: %v", err)
+	}
+
+	if len(table.Columns) != 5 {
+		t.Fatalf("Expected 5 columns, got %d", len(table.Columns))
+	}
+
+	id, _ := table.Column("id")
+	if !id.Primary || id.Type != "TEXT" {
+		t.Errorf("Expected id to be a TEXT primary key, got %+v", id)
+	}
+
+	age, _ := table.Column("age")
+	if !age.Nullable {
+		t.Error("Expected age to be nullable")
+	}
+
+	active, _ := table.Column("active")
+	if active.Nullable || active.Default == nil || *active.Default != "true" {
+		t.Errorf("Expected active to be NOT NULL with default true, got %+v", active)
+	}
+}
+
+func TestGetIndexes(t *testing.T) {
+	setupIntrospectionDB(t)
+
+	indexes, err := GetIndexes("users")
+	if err != nil {
+		t.Fatalf("Failed to get indexes: %v", err)
+	}
+
+	var found bool
+	for _, index := range indexes {
+		if index.Name == "idx_users_business_age" {
+			found = true
+			if index.Unique {
+				t.Error("Expected idx_users_business_age not to be unique")
+			}
+			if len(index.Columns) != 2 || index.Columns[0] != "business_id" || index.Columns[1] != "age" {
+				t.Errorf("Expected columns [business_id age], got %v", index.Columns)
+			}
+		}
+	}
+	if !found {
+		t.Errorf("Expected idx_users_business_age in %v", indexes)
+	}
+
+	if ok, err := HasIndex("users", "idx_users_business_age"); err != nil || !ok {
+		t.Errorf("Expected HasIndex to find idx_users_business_age, got %v, %v", ok, err)
+	}
+}
+
+func TestGetForeignKeys(t *testing.T) {
+	setupIntrospectionDB(t)
+
+	foreignKeys, err := GetForeignKeys("users")
+	if err != nil {
+		t.Fatalf("Failed to get foreign keys: %v", err)
+	}
+
+	if len(foreignKeys) != 1 {
+		t.Fatalf("Expected 1 foreign key, got %d", len(foreignKeys))
+	}
+
+	fk := foreignKeys[0]
+	if fk.RefTable != "businesses" || fk.Columns[0] != "business_id" || fk.RefColumns[0] != "id" {
+		t.Errorf("Unexpected foreign key %+v", fk)
+	}
+	if fk.OnDelete != "CASCADE" {
+		t.Errorf("Expected ON DELETE CASCADE, got %s", fk.OnDelete)
+	}
+}
+
+func TestMySQLIndexQuery(t *testing.T) {
+	if query := mysqlIndexQuery(true); !strings.Contains(query, "COALESCE(COLUMN_NAME, EXPRESSION)") {
+		t.Errorf("Expected functional index expressions to be read:\n%s", query)
+	}
+	if query := mysqlIndexQuery(false); strings.Contains(query, "EXPRESSION") {
+		t.Errorf("Expected no EXPRESSION column for servers without it:\n%s", query)
+	}
+}
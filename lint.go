@@ -0,0 +1,257 @@
+package olympian
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type Severity string
+
+const (
+	SeverityOff     Severity = "off"
+	SeverityWarning Severity = "warning"
+	SeverityError   Severity = "error"
+)
+
+const (
+	RuleNotNullWithoutDefault   = "not-null-without-default"
+	RuleDropColumn              = "drop-column"
+	RuleDropTable               = "drop-table"
+	RuleIndexWithoutConcurrency = "index-without-concurrently"
+	RuleTypeNarrowing           = "type-narrowing"
+)
+
+var DefaultLintSeverities = map[string]Severity{
+	RuleNotNullWithoutDefault:   SeverityError,
+	RuleDropColumn:              SeverityError,
+	RuleDropTable:               SeverityError,
+	RuleIndexWithoutConcurrency: SeverityWarning,
+	RuleTypeNarrowing:           SeverityError,
+}
+
+type LintIssue struct {
+	Migration string
+	Rule      string
+	Severity  Severity
+	Message   string
+}
+
+func (i LintIssue) String() string {
+	return fmt.Sprintf("[%s] %s: %s (%s)", i.Severity, i.Migration, i.Message, i.Rule)
+}
+
+// ParseLintSeverities reads rule=severity pairs, e.g. drop-column=warning,
+// on top of DefaultLintSeverities.
+func ParseLintSeverities(args []string) (map[string]Severity, error) {
+	severities := make(map[string]Severity, len(DefaultLintSeverities))
+	for rule, severity := range DefaultLintSeverities {
+		severities[rule] = severity
+	}
+
+	for _, arg := range args {
+		for _, pair := range strings.Split(arg, ",") {
+			rule, value, ok := strings.Cut(strings.TrimSpace(pair), "=")
+			if !ok {
+				return nil, fmt.Errorf("expected rule=severity, got %q", pair)
+			}
+			if _, known := DefaultLintSeverities[rule]; !known {
+				return nil, fmt.Errorf("unknown lint rule %q", rule)
+			}
+			severity := Severity(strings.ToLower(value))
+			switch severity {
+			case SeverityOff, SeverityWarning, SeverityError:
+				severities[rule] = severity
+			default:
+				return nil, fmt.Errorf("unknown severity %q for rule %s", value, rule)
+			}
+		}
+	}
+	return severities, nil
+}
+
+func HasLintErrors(issues []LintIssue) bool {
+	for _, issue := range issues {
+		if issue.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// Lint runs the pending migrations in pretend mode and reports risky
+// operations. Rules missing from severities use DefaultLintSeverities.
+func (m *Migrator) Lint(migrations []Migration, severities map[string]Severity) ([]LintIssue, error) {
+	plans, err := m.Pretend(migrations)
+	if err != nil {
+		return nil, err
+	}
+
+	severity := func(rule string) Severity {
+		if s, ok := severities[rule]; ok {
+			return s
+		}
+		return DefaultLintSeverities[rule]
+	}
+
+	var issues []LintIssue
+	report := func(migration, rule, format string, args ...interface{}) {
+		if s := severity(rule); s != SeverityOff {
+			issues = append(issues, LintIssue{
+				Migration: migration,
+				Rule:      rule,
+				Severity:  s,
+				Message:   fmt.Sprintf(format, args...),
+			})
+		}
+	}
+
+	for _, plan := range plans {
+		for _, op := range plan.Operations {
+			switch op.Kind {
+			case OpModifyTable, OpChangeColumn:
+				if _, ok := m.dialect.(*PostgresDialect); ok && addsIndex(op) {
+					report(plan.Migration, RuleIndexWithoutConcurrency,
+						"index on %s is created without CONCURRENTLY and blocks writes while it builds", op.Table)
+				}
+				for _, col := range op.columns {
+					if col.change {
+						// Changing an enum adds values, which never narrows it.
+						if col.dataType != "enum" && col.existing != nil && narrows(col.existing.Type, m.dialect.GetDataType(col)) {
+							report(plan.Migration, RuleTypeNarrowing,
+								"column %s.%s is narrowed from %s to %s", op.Table, col.name, col.existing.Type, m.dialect.GetDataType(col))
+						}
+						continue
+					}
+					if !col.nullable && col.defaultValue == nil && !col.primary && !col.autoIncrement && col.generatedAs == "" {
+						report(plan.Migration, RuleNotNullWithoutDefault,
+							"column %s.%s is added as NOT NULL without a default", op.Table, col.name)
+					}
+				}
+			case OpDropColumn:
+				if m.hasRows(op.Table) {
+					report(plan.Migration, RuleDropColumn,
+						"column %s.%s is dropped from a table that contains data", op.Table, op.column)
+				}
+			case OpDropTable:
+				if m.hasRows(op.Table) {
+					report(plan.Migration, RuleDropTable, "table %s is dropped but contains data", op.Table)
+				}
+			case OpCreateIndex:
+				if _, ok := m.dialect.(*PostgresDialect); ok && !concurrently(op) {
+					report(plan.Migration, RuleIndexWithoutConcurrency,
+						"index on %s is created without CONCURRENTLY and blocks writes while it builds", op.Table)
+				}
+			}
+		}
+	}
+
+	sort.SliceStable(issues, func(i, j int) bool {
+		return issues[i].Migration < issues[j].Migration
+	})
+	return issues, nil
+}
+
+// addsIndex reports whether a Modify creates indexes, which it always does
+// without CONCURRENTLY.
+func addsIndex(op Operation) bool {
+	for _, query := range op.SQL {
+		if strings.HasPrefix(query, "CREATE INDEX") || strings.HasPrefix(query, "CREATE UNIQUE INDEX") {
+			return true
+		}
+	}
+	return false
+}
+
+func concurrently(op Operation) bool {
+	for _, query := range op.SQL {
+		if strings.Contains(strings.ToUpper(query), "CONCURRENTLY") {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *Migrator) hasRows(tableName string) bool {
+	var one int
+	err := m.db.QueryRow(fmt.Sprintf("SELECT 1 FROM %s LIMIT 1", m.dialect.QuoteIdentifier(tableName))).Scan(&one)
+	return err == nil
+}
+
+var typeSizePattern = regexp.MustCompile(`\((\d+)(?:,\s*(\d+))?\)`)
+
+// narrows reports whether changing a column from one SQL type to another
+// can lose data: a smaller integer, a shorter string or a move to an
+// unrelated type family.
+func narrows(from, to string) bool {
+	fromFamily, fromRank := typeFamily(from)
+	toFamily, toRank := typeFamily(to)
+
+	if fromFamily == "" || toFamily == "" || strings.EqualFold(from, to) {
+		return false
+	}
+	if fromFamily != toFamily {
+		return !(fromFamily == "integer" && (toFamily == "numeric" || toFamily == "string"))
+	}
+	if toRank < fromRank {
+		return true
+	}
+
+	fromSize, fromScale := typeSize(from)
+	toSize, toScale := typeSize(to)
+	if fromSize == 0 {
+		return toSize > 0 && fromFamily == "string"
+	}
+	return (toSize > 0 && toSize < fromSize) || toScale < fromScale
+}
+
+func typeFamily(sqlType string) (string, int) {
+	t := strings.ToLower(sqlType)
+	switch {
+	case strings.HasPrefix(t, "tinyint(1)"), strings.HasPrefix(t, "bool"):
+		return "boolean", 0
+	case strings.HasPrefix(t, "tinyint"):
+		return "integer", 1
+	case strings.HasPrefix(t, "smallint"):
+		return "integer", 2
+	case strings.HasPrefix(t, "mediumint"):
+		return "integer", 3
+	case strings.HasPrefix(t, "bigint"), t == "bigserial":
+		return "integer", 5
+	case strings.HasPrefix(t, "int"), t == "serial":
+		return "integer", 4
+	case strings.HasPrefix(t, "decimal"), strings.HasPrefix(t, "numeric"):
+		return "numeric", 0
+	case strings.HasPrefix(t, "real"), strings.HasPrefix(t, "float"):
+		return "float", 1
+	case strings.HasPrefix(t, "double"):
+		return "float", 2
+	case strings.HasPrefix(t, "char"), strings.HasPrefix(t, "character"), strings.HasPrefix(t, "varchar"):
+		return "string", 1
+	case strings.HasPrefix(t, "tinytext"):
+		return "string", 0
+	case t == "text", strings.HasPrefix(t, "mediumtext"), strings.HasPrefix(t, "longtext"):
+		return "string", 2
+	case strings.HasPrefix(t, "timestamp"), strings.HasPrefix(t, "datetime"):
+		return "datetime", 1
+	case t == "date":
+		return "datetime", 0
+	case strings.HasPrefix(t, "json"):
+		return "json", 0
+	case t == "uuid":
+		return "uuid", 0
+	}
+	return "", 0
+}
+
+func typeSize(sqlType string) (int, int) {
+	m := typeSizePattern.FindStringSubmatch(sqlType)
+	if m == nil {
+		return 0, 0
+	}
+	size, _ := strconv.Atoi(m[1])
+	scale, _ := strconv.Atoi(m[2])
+	return size, scale
+}
@@ -0,0 +1,302 @@
+package olympian
+
+import (
+	"testing"
+)
+
+func TestMigratorPretend(t *testing.T) {
+	db := setupTestDB(t)
+	defer func() { _ = db.Close() }()
+
+	migrator := NewMigrator(db, &SQLiteDialect{})
+	if err := migrator.Init(); err != nil {
+		t.Fatalf("Failed to initialize migrator: %v", err)
+	}
+
+	migrations := []Migration{
+		{
+			Name: "1_create_users_table",
+			Up: func() error {
+				return Table("users").Create(func() {
+					Uuid("id").Primary()
+					String("name")
+				})
+			},
+			Down: func() error { return Table("users").Drop() },
+		},
+	}
+
+	plans, err := migrator.Pretend(migrations)
+	if err != nil {
+		t.Fatalf("Failed to pretend: %v", err)
+	}
+	if len(plans) != 1 || len(plans[0].Operations) != 1 || plans[0].Operations[0].Kind != OpCreateTable {
+		t.Fatalf("Unexpected plans: %+v", plans)
+	}
+
+	if ok, _ := HasTable("users"); ok {
+		t.Error("Expected pretend mode not to create the users table")
+	}
+}
+
+func TestMigratorLint(t *testing.T) {
+	db := setupTestDB(t)
+	defer func() { _ = db.Close() }()
+
+	migrator := NewMigrator(db, &SQLiteDialect{})
+	if err := migrator.Init(); err != nil {
+		t.Fatalf("Failed to initialize migrator: %v", err)
+	}
+
+	setup := []Migration{
+		{
+			Name: "1_create_users_table",
+			Up: func() error {
+				return Table("users").Create(func() {
+					Uuid("id").Primary()
+					String("name")
+					String("legacy").Nullable()
+				})
+			},
+			Down: func() error { return Table("users").Drop() },
+		},
+		{
+			Name: "2_create_logs_table",
+			Up: func() error {
+				return Table("logs").Create(func() {
+					Integer("id").Primary()
+				})
+			},
+			Down: func() error { return Table("logs").Drop() },
+		},
+	}
+	if err := migrator.Migrate(setup); err != nil {
+		t.Fatalf("Failed to run migrations: %v", err)
+	}
+	if _, err := db.Exec("INSERT INTO users (id, name) VALUES ('1', 'Ada')"); err != nil {
+		t.Fatalf("Failed to insert user: %v", err)
+	}
+
+	migrations := append(setup, Migration{
+		Name: "3_risky_changes",
+		Up: func() error {
+			if err := Table("users").Modify(func() {
+				String("email")
+				String("nickname").Nullable()
+				Boolean("active").Default(true)
+			}); err != nil {
+				return err
+			}
+			if err := Table("users").DropColumn("legacy"); err != nil {
+				return err
+			}
+			if err := Table("logs").Drop(); err != nil {
+				return err
+			}
+			return Table("users").Drop()
+		},
+		Down: func() error { return nil },
+	})
+
+	issues, err := migrator.Lint(migrations, nil)
+	if err != nil {
+		t.Fatalf("Failed to lint: %v", err)
+	}
+
+	expected := []string{RuleNotNullWithoutDefault, RuleDropColumn, RuleDropTable}
+	if len(issues) != len(expected) {
+		t.Fatalf("Expected %d issues, got %d: %v", len(expected), len(issues), issues)
+	}
+	for i, rule := range expected {
+		if issues[i].Rule != rule || issues[i].Migration != "3_risky_changes" {
+			t.Errorf("Expected issue %d to be %s, got %v", i, rule, issues[i])
+		}
+	}
+	if !HasLintErrors(issues) {
+		t.Error("Expected lint errors")
+	}
+
+	if ok, _ := HasColumn("users", "legacy"); !ok {
+		t.Error("Expected lint not to drop users.legacy")
+	}
+
+	severities, err := ParseLintSeverities([]string{"drop-column=warning", "drop-table=off,not-null-without-default=off"})
+	if err != nil {
+		t.Fatalf("Failed to parse severities: %v", err)
+	}
+	issues, err = migrator.Lint(migrations, severities)
+	if err != nil {
+		t.Fatalf("Failed to lint: %v", err)
+	}
+	if len(issues) != 1 || issues[0].Severity != SeverityWarning || HasLintErrors(issues) {
+		t.Errorf("Expected a single drop-column warning, got %v", issues)
+	}
+}
+
+func TestParseLintSeveritiesErrors(t *testing.T) {
+	for _, arg := range []string{"drop-column", "unknown=error", "drop-column=fatal"} {
+		if _, err := ParseLintSeverities([]string{arg}); err == nil {
+			t.Errorf("Expected an error for %q", arg)
+		}
+	}
+}
+
+func TestNarrows(t *testing.T) {
+	tests := []struct {
+		from, to string
+		expected bool
+	}{
+		{"VARCHAR(255)", "VARCHAR(100)", true},
+		{"VARCHAR(100)", "VARCHAR(255)", false},
+		{"TEXT", "VARCHAR(255)", true},
+		{"BIGINT", "INTEGER", true},
+		{"INTEGER", "BIGINT", false},
+		{"DECIMAL(10,2)", "DECIMAL(10,0)", true},
+		{"INTEGER", "DECIMAL(10,2)", false},
+		{"TEXT", "INTEGER", true},
+	}
+	for _, tt := range tests {
+		if result := narrows(tt.from, tt.to); result != tt.expected {
+			t.Errorf("narrows(%s, %s) = %v, expected %v", tt.from, tt.to, result, tt.expected)
+		}
+	}
+}
+
+func TestMigratorLintTypeNarrowing(t *testing.T) {
+	db := setupTestDB(t)
+	defer func() { _ = db.Close() }()
+
+	migrator := NewMigrator(db, &SQLiteDialect{})
+	if err := migrator.Init(); err != nil {
+		t.Fatalf("Failed to initialize migrator: %v", err)
+	}
+
+	migrations := []Migration{
+		{
+			Name: "1_create_users_table",
+			Up: func() error {
+				return Table("users").Create(func() {
+					Uuid("id").Primary()
+					String("score")
+				})
+			},
+			Down: func() error { return Table("users").Drop() },
+		},
+	}
+	if err := migrator.Migrate(migrations); err != nil {
+		t.Fatalf("Failed to run migrations: %v", err)
+	}
+
+	migrations = append(migrations, Migration{
+		Name: "2_change_score_to_integer",
+		Up: func() error {
+			return Table("users").Modify(func() {
+				Integer("score").Change()
+			})
+		},
+		Down: func() error { return nil },
+	})
+
+	issues, err := migrator.Lint(migrations, nil)
+	if err != nil {
+		t.Fatalf("Failed to lint: %v", err)
+	}
+	if len(issues) != 1 || issues[0].Rule != RuleTypeNarrowing {
+		t.Errorf("Expected a type-narrowing issue, got %v", issues)
+	}
+}
+
+func TestLintPendingMigrationsBuildOnEachOther(t *testing.T) {
+	db := setupTestDB(t)
+	defer func() { _ = db.Close() }()
+
+	migrator := NewMigrator(db, &SQLiteDialect{})
+	if err := migrator.Init(); err != nil {
+		t.Fatalf("Failed to initialize migrator: %v", err)
+	}
+
+	migrations := []Migration{
+		{
+			Name: "1_create_posts_table",
+			Up: func() error {
+				return Table("posts").Create(func() {
+					Integer("id").Primary()
+					Integer("n").Index()
+					String("title")
+				})
+			},
+		},
+		{
+			Name: "2_change_posts",
+			Up: func() error {
+				if err := Table("posts").Modify(func() {
+					BigInteger("n").Change()
+				}); err != nil {
+					return err
+				}
+				if err := Table("posts").DropColumn("title"); err != nil {
+					return err
+				}
+				if err := DropIndex("posts_n_index"); err != nil {
+					return err
+				}
+				db, _ := GetDB()
+				_, err := db.Exec("INSERT INTO olympian_migrations (migration, batch) VALUES ('bogus', 1)")
+				return err
+			},
+		},
+	}
+
+	if _, err := migrator.Lint(migrations, nil); err != nil {
+		t.Fatalf("Failed to lint: %v", err)
+	}
+
+	plans, err := migrator.Pretend(migrations)
+	if err != nil {
+		t.Fatalf("Failed to pretend: %v", err)
+	}
+	ops := plans[1].Operations
+	if len(ops) != 4 || ops[0].SQL[0] != "-- rebuild table posts, created by an earlier pending migration" ||
+		ops[2].SQL[0] != "DROP INDEX IF EXISTS posts_n_index" || ops[3].Kind != OpRawSQL {
+		t.Errorf("Unexpected operations: %+v", ops)
+	}
+
+	executed, err := migrator.GetExecutedMigrations()
+	if err != nil {
+		t.Fatalf("Failed to get executed migrations: %v", err)
+	}
+	if len(executed) != 0 {
+		t.Errorf("Expected raw SQL not to run during lint, got %v", executed)
+	}
+}
+
+func TestLintIgnoresGeneratedColumns(t *testing.T) {
+	db := setupTestDB(t)
+	defer func() { _ = db.Close() }()
+
+	migrator := NewMigrator(db, &SQLiteDialect{})
+	if err := migrator.Init(); err != nil {
+		t.Fatalf("Failed to initialize migrator: %v", err)
+	}
+	if _, err := db.Exec("CREATE TABLE people (id INTEGER PRIMARY KEY, first_name TEXT, last_name TEXT)"); err != nil {
+		t.Fatalf("Failed to create table: %v", err)
+	}
+
+	migrations := []Migration{{
+		Name: "1_add_full_name",
+		Up: func() error {
+			return Table("people").Modify(func() {
+				String("full_name").VirtualAs("first_name || ' ' || last_name")
+				String("initials").StoredAs("substr(first_name, 1, 1) || substr(last_name, 1, 1)")
+			})
+		},
+	}}
+
+	issues, err := migrator.Lint(migrations, nil)
+	if err != nil {
+		t.Fatalf("Failed to lint: %v", err)
+	}
+	if len(issues) != 0 {
+		t.Errorf("Expected generated columns not to be flagged, got %v", issues)
+	}
+}
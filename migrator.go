@@ -1,16 +1,23 @@
 package olympian
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 )
 
+// Migration bodies resolve their connection through SetDB/GetDB, so only one
+// migration may run at a time even when several migrators are in use.
+var execMu sync.Mutex
+
 type Migrator struct {
-	db      *sql.DB
-	dialect Dialect
+	db         *sql.DB
+	dialect    Dialect
+	searchPath []string
 }
 
 func NewMigrator(db *sql.DB, dialect Dialect) *Migrator {
@@ -20,8 +27,17 @@ func NewMigrator(db *sql.DB, dialect Dialect) *Migrator {
 	}
 }
 
+// SearchPath sets the PostgreSQL search_path migration bodies run with, so
+// unqualified names resolve in schemas, in order. Schemas may also be given
+// as a comma-separated list. olympian_migrations keeps resolving through the
+// connection's own search_path.
+func (m *Migrator) SearchPath(schemas ...string) *Migrator {
+	m.searchPath = searchPathNames(schemas)
+	return m
+}
+
 func (m *Migrator) Init() error {
-	SetDB(m.db, m.dialect)
+	m.use()
 
 	createTableSQL := `
 	CREATE TABLE IF NOT EXISTS olympian_migrations (
@@ -84,7 +100,11 @@ func (m *Migrator) GetExecutedMigrations() (map[string]bool, error) {
 }
 
 func (m *Migrator) RecordMigration(name string, batch int) error {
-	_, err := m.db.Exec(
+	return m.recordMigration(m.db, name, batch)
+}
+
+func (m *Migrator) recordMigration(q Querier, name string, batch int) error {
+	_, err := q.Exec(
 		"INSERT INTO olympian_migrations (migration, batch, executed_at) VALUES (?, ?, ?)",
 		name, batch, time.Now(),
 	)
@@ -92,7 +112,11 @@ func (m *Migrator) RecordMigration(name string, batch int) error {
 }
 
 func (m *Migrator) RemoveMigration(name string) error {
-	_, err := m.db.Exec("DELETE FROM olympian_migrations WHERE migration = ?", name)
+	return m.removeMigration(m.db, name)
+}
+
+func (m *Migrator) removeMigration(q Querier, name string) error {
+	_, err := q.Exec("DELETE FROM olympian_migrations WHERE migration = ?", name)
 	return err
 }
 
@@ -118,11 +142,11 @@ func (m *Migrator) GetMigrationsFromBatch(batch int) ([]string, error) {
 }
 
 func (m *Migrator) Migrate(migrations []Migration) error {
-	SetDB(m.db, m.dialect)
+	m.use()
 
-	executed, err := m.GetExecutedMigrations()
+	pending, err := m.pendingMigrations(migrations)
 	if err != nil {
-		return fmt.Errorf("failed to get executed migrations: %w", err)
+		return err
 	}
 
 	batch, err := m.GetLastBatch()
@@ -131,33 +155,24 @@ func (m *Migrator) Migrate(migrations []Migration) error {
 	}
 	batch++
 
-	var pending []Migration
-	for _, migration := range migrations {
-		if !executed[migration.Name] {
-			pending = append(pending, migration)
-		}
-	}
-
 	if len(pending) == 0 {
 		fmt.Println("Nothing to migrate")
 		return nil
 	}
 
-	sort.Slice(pending, func(i, j int) bool {
-		return pending[i].Name < pending[j].Name
-	})
-
 	for _, migration := range pending {
 		fmt.Printf("Migrating: %s\n", migration.Name)
 
-		if err := migration.Up(); err != nil {
+		err := m.run(migration, migration.Up, func(q Querier) error {
+			if err := m.recordMigration(q, migration.Name, batch); err != nil {
+				return fmt.Errorf("failed to record migration %s: %w", migration.Name, err)
+			}
+			return nil
+		})
+		if err != nil {
 			return fmt.Errorf("migration %s failed: %w", migration.Name, err)
 		}
 
-		if err := m.RecordMigration(migration.Name, batch); err != nil {
-			return fmt.Errorf("failed to record migration %s: %w", migration.Name, err)
-		}
-
 		fmt.Printf("Migrated:  %s\n", migration.Name)
 	}
 
@@ -165,7 +180,7 @@ func (m *Migrator) Migrate(migrations []Migration) error {
 }
 
 func (m *Migrator) Rollback(migrations []Migration, steps int) error {
-	SetDB(m.db, m.dialect)
+	m.use()
 
 	if steps <= 0 {
 		steps = 1
@@ -209,14 +224,16 @@ func (m *Migrator) Rollback(migrations []Migration, steps int) error {
 
 			fmt.Printf("Rolling back: %s\n", name)
 
-			if err := migration.Down(); err != nil {
+			err := m.run(migration, migration.Down, func(q Querier) error {
+				if err := m.removeMigration(q, name); err != nil {
+					return fmt.Errorf("failed to remove migration record %s: %w", name, err)
+				}
+				return nil
+			})
+			if err != nil {
 				return fmt.Errorf("rollback %s failed: %w", name, err)
 			}
 
-			if err := m.RemoveMigration(name); err != nil {
-				return fmt.Errorf("failed to remove migration record %s: %w", name, err)
-			}
-
 			fmt.Printf("Rolled back: %s\n", name)
 		}
 	}
@@ -224,9 +241,243 @@ func (m *Migrator) Rollback(migrations []Migration, steps int) error {
 	return nil
 }
 
-func (m *Migrator) Status(migrations []Migration) error {
+func (m *Migrator) use() {
+	execMu.Lock()
+	defer execMu.Unlock()
+
+	SetDB(m.db, m.dialect)
+}
+
+// restoreDB points SetDB back at db and dialect if it still refers to closed,
+// the connection of a migrator that is done.
+func restoreDB(closed *sql.DB, db *sql.DB, dialect Dialect) {
+	execMu.Lock()
+	defer execMu.Unlock()
+
+	if current, _ := currentDB(); current == closed {
+		SetDB(db, dialect)
+	}
+}
+
+// run executes fn followed by finish, which updates olympian_migrations. Both
+// share one transaction unless the migration is non-transactional or the
+// database is MySQL, whose DDL statements commit implicitly.
+func (m *Migrator) run(migration Migration, fn func() error, finish func(q Querier) error) error {
+	execMu.Lock()
+	defer execMu.Unlock()
+
 	SetDB(m.db, m.dialect)
 
+	if _, isMySQL := m.dialect.(*MySQLDialect); isMySQL || migration.NonTransactional {
+		if err := m.pinned(func(Querier) error { return fn() }); err != nil {
+			return err
+		}
+		return finish(m.db)
+	}
+
+	// SQLite only changes foreign key enforcement outside a transaction and
+	// per connection, so the migration gets a connection of its own with
+	// enforcement off. Otherwise dropping a table during a rebuild would
+	// cascade to the rows referencing it. The keys are checked before commit.
+	ctx := context.Background()
+	c, err := m.db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get connection: %w", err)
+	}
+	defer func() { _ = c.Close() }()
+
+	var checkForeignKeys bool
+	if _, isSQLite := m.dialect.(*SQLiteDialect); isSQLite {
+		if err := c.QueryRowContext(ctx, "PRAGMA foreign_keys").Scan(&checkForeignKeys); err != nil {
+			return fmt.Errorf("failed to read foreign key setting: %w", err)
+		}
+		if checkForeignKeys {
+			if _, err := c.ExecContext(ctx, "PRAGMA foreign_keys = OFF"); err != nil {
+				return fmt.Errorf("failed to disable foreign keys: %w", err)
+			}
+			defer func() { _, _ = c.ExecContext(ctx, "PRAGMA foreign_keys = ON") }()
+		}
+	}
+
+	tx, err := c.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	setTx(tx)
+	defer setTx(nil)
+
+	restore, err := m.setSearchPath(tx, true)
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	if err := fn(); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	if err := restore(); err != nil {
+		_ = tx.Rollback()
+		return fmt.Errorf("failed to restore search_path: %w", err)
+	}
+	if err := finish(tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	if checkForeignKeys {
+		if err := checkSQLiteForeignKeys(tx); err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (m *Migrator) record(fn func() error, state *pretendPlan) ([]Operation, error) {
+	execMu.Lock()
+	defer execMu.Unlock()
+
+	SetDB(m.db, m.dialect)
+
+	var ops []Operation
+	err := m.pinned(func(q Querier) error {
+		mu.Lock()
+		recorder = &ops
+		plan = state
+		setScope(scopedDB(q, &ops))
+		mu.Unlock()
+		defer func() {
+			mu.Lock()
+			recorder = nil
+			plan = nil
+			setScope(nil)
+			mu.Unlock()
+		}()
+
+		return fn()
+	})
+	return ops, err
+}
+
+// pinned runs fn outside a transaction. With a search path set, migration
+// bodies are bound to a single connection that has it applied, which is
+// restored before the connection goes back to the pool. The caller holds
+// execMu.
+func (m *Migrator) pinned(fn func(q Querier) error) error {
+	if len(m.searchPath) == 0 {
+		return fn(m.db)
+	}
+
+	c, err := m.db.Conn(context.Background())
+	if err != nil {
+		return fmt.Errorf("failed to get connection: %w", err)
+	}
+	defer func() { _ = c.Close() }()
+
+	q := connQuerier{c}
+	restore, err := m.setSearchPath(q, false)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = restore() }()
+
+	db := scopedDB(q, nil)
+	defer func() { _ = db.Close() }()
+	SetDB(db, m.dialect)
+	defer SetDB(m.db, m.dialect)
+
+	return fn(q)
+}
+
+// setSearchPath applies the migrator's search path to q, only for the
+// current transaction when local is set, and returns a function that puts
+// the previous one back.
+func (m *Migrator) setSearchPath(q Querier, local bool) (func() error, error) {
+	if len(m.searchPath) == 0 {
+		return func() error { return nil }, nil
+	}
+	if _, isPostgres := m.dialect.(*PostgresDialect); !isPostgres {
+		return nil, fmt.Errorf("search path %s: only PostgreSQL supports search_path", strings.Join(m.searchPath, ","))
+	}
+
+	var previous string
+	if err := q.QueryRow("SELECT current_setting('search_path')").Scan(&previous); err != nil {
+		return nil, fmt.Errorf("failed to read search_path: %w", err)
+	}
+
+	schemas := make([]string, len(m.searchPath))
+	for i, schema := range m.searchPath {
+		schemas[i] = m.dialect.QuoteIdentifier(schema)
+	}
+	set := func(path string) error {
+		_, err := q.Exec("SELECT set_config('search_path', $1, $2)", path, local)
+		return err
+	}
+	if err := set(strings.Join(schemas, ", ")); err != nil {
+		return nil, fmt.Errorf("failed to set search_path: %w", err)
+	}
+	return func() error { return set(previous) }, nil
+}
+
+func (m *Migrator) Pending(migrations []Migration) ([]string, error) {
+	pending, err := m.pendingMigrations(migrations)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(pending))
+	for _, migration := range pending {
+		names = append(names, migration.Name)
+	}
+	return names, nil
+}
+
+func (m *Migrator) pendingMigrations(migrations []Migration) ([]Migration, error) {
+	executed, err := m.GetExecutedMigrations()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get executed migrations: %w", err)
+	}
+
+	var pending []Migration
+	for _, migration := range migrations {
+		if !executed[migration.Name] {
+			pending = append(pending, migration)
+		}
+	}
+
+	sort.Slice(pending, func(i, j int) bool {
+		return pending[i].Name < pending[j].Name
+	})
+	return pending, nil
+}
+
+// Pretend runs the Up function of every pending migration without touching
+// the schema and returns the operations each one would perform. Operations on
+// tables created by an earlier pending migration cannot look at the table, so
+// changed columns keep no existing modifiers and SQLite rebuilds are shown as
+// a comment.
+func (m *Migrator) Pretend(migrations []Migration) ([]MigrationPlan, error) {
+	m.use()
+
+	pending, err := m.pendingMigrations(migrations)
+	if err != nil {
+		return nil, err
+	}
+
+	state := newPretendPlan()
+	var plans []MigrationPlan
+	for _, migration := range pending {
+		ops, err := m.record(migration.Up, state)
+		if err != nil {
+			return nil, fmt.Errorf("migration %s failed: %w", migration.Name, err)
+		}
+		plans = append(plans, MigrationPlan{Migration: migration.Name, Operations: ops})
+	}
+	return plans, nil
+}
+
+func (m *Migrator) Status(migrations []Migration) error {
+	m.use()
+
 	executed, err := m.GetExecutedMigrations()
 	if err != nil {
 		return fmt.Errorf("failed to get executed migrations: %w", err)
@@ -253,7 +504,7 @@ func (m *Migrator) Status(migrations []Migration) error {
 }
 
 func (m *Migrator) Reset(migrations []Migration) error {
-	SetDB(m.db, m.dialect)
+	m.use()
 
 	lastBatch, err := m.GetLastBatch()
 	if err != nil {
@@ -268,37 +519,59 @@ func (m *Migrator) Reset(migrations []Migration) error {
 	return m.Rollback(migrations, lastBatch)
 }
 
+// freshSchema reports whether Fresh drops the table or view, which it does
+// in the current schema and in the schemas set with SearchPath, but not in
+// the other schemas on the connection's search path.
+func (m *Migrator) freshSchema(name string) bool {
+	schema, _ := splitTableName(name)
+	return schema == "" || containsString(m.searchPath, schema)
+}
+
 func (m *Migrator) Fresh(migrations []Migration) error {
-	SetDB(m.db, m.dialect)
+	m.use()
 
-	rows, err := m.db.Query("SELECT name FROM sqlite_master WHERE type='table' AND name NOT LIKE 'sqlite_%'")
+	// Views go first, as PostgreSQL refuses to drop the tables they read.
+	views, err := m.dialect.GetViews(m.db)
 	if err != nil {
-		if _, ok := m.dialect.(*PostgresDialect); ok {
-			rows, err = m.db.Query("SELECT tablename FROM pg_tables WHERE schemaname='public'")
-		} else if _, ok := m.dialect.(*MySQLDialect); ok {
-			rows, err = m.db.Query("SHOW TABLES")
-		}
-		if err != nil {
-			return fmt.Errorf("failed to get tables: %w", err)
-		}
+		return fmt.Errorf("failed to get views: %w", err)
 	}
-	defer func() { _ = rows.Close() }()
 
-	var tables []string
-	for rows.Next() {
-		var table string
-		if err := rows.Scan(&table); err != nil {
-			return err
+	for _, view := range views {
+		if !m.freshSchema(view.Name) {
+			continue
+		}
+		query := dropViewSQL(m.dialect, view)
+		if _, isPostgres := m.dialect.(*PostgresDialect); isPostgres {
+			query += " CASCADE"
 		}
-		if table != "olympian_migrations" {
-			tables = append(tables, table)
+		if _, err := m.db.Exec(query); err != nil {
+			return fmt.Errorf("failed to drop view %s: %w", view.Name, err)
 		}
 	}
 
+	tables, err := m.dialect.GetTables(m.db)
+	if err != nil {
+		return fmt.Errorf("failed to get tables: %w", err)
+	}
+
 	for _, table := range tables {
-		if _, err := m.db.Exec(fmt.Sprintf("DROP TABLE IF EXISTS %s", table)); err != nil {
+		if _, name := splitTableName(table); name == "olympian_migrations" || !m.freshSchema(table) {
+			continue
+		}
+		var types []string
+		if _, isPostgres := m.dialect.(*PostgresDialect); isPostgres {
+			if types, err = postgresEnumTypes(m.db, table); err != nil {
+				return err
+			}
+		}
+		if _, err := m.db.Exec(m.dialect.BuildDropTable(table)); err != nil {
 			return fmt.Errorf("failed to drop table %s: %w", table, err)
 		}
+		for _, name := range types {
+			if _, err := m.db.Exec(fmt.Sprintf("DROP TYPE IF EXISTS %s", m.dialect.QuoteIdentifier(name))); err != nil {
+				return fmt.Errorf("failed to drop type %s: %w", name, err)
+			}
+		}
 	}
 
 	if _, err := m.db.Exec("DELETE FROM olympian_migrations"); err != nil {
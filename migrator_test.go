@@ -2,6 +2,11 @@ package olympian
 
 import (
 	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
 	"testing"
 
 	_ "github.com/mattn/go-sqlite3"
@@ -331,3 +336,323 @@ func TestRecordAndRemoveMigration(t *testing.T) {
 		t.Errorf("Expected 0 migration records after removal, got %d", count)
 	}
 }
+
+func TestMigratorRollsBackFailedMigration(t *testing.T) {
+	db := setupTestDB(t)
+	defer func() { _ = db.Close() }()
+
+	migrator := NewMigrator(db, &SQLiteDialect{})
+	if err := migrator.Init(); err != nil {
+		t.Fatalf("Failed to initialize migrator: %v", err)
+	}
+
+	failing := func(nonTransactional bool) Migration {
+		return Migration{
+			Name: "create_users_table",
+			Up: func() error {
+				if err := Table("users").Create(func() {
+					Uuid("id").Primary()
+				}); err != nil {
+					return err
+				}
+				return Table("missing").DropColumn("id")
+			},
+			Down:             func() error { return Table("users").Drop() },
+			NonTransactional: nonTransactional,
+		}
+	}
+
+	if err := migrator.Migrate([]Migration{failing(false)}); err == nil {
+		t.Fatal("Expected migration to fail")
+	}
+	if ok, _ := HasTable("users"); ok {
+		t.Error("Expected users table to be rolled back")
+	}
+
+	if err := migrator.Migrate([]Migration{failing(true)}); err == nil {
+		t.Fatal("Expected migration to fail")
+	}
+	if ok, _ := HasTable("users"); !ok {
+		t.Error("Expected non-transactional migration to leave users table behind")
+	}
+
+	executed, err := migrator.GetExecutedMigrations()
+	if err != nil {
+		t.Fatalf("Failed to get executed migrations: %v", err)
+	}
+	if len(executed) != 0 {
+		t.Errorf("Expected no recorded migrations, got %v", executed)
+	}
+}
+
+func TestMigrationRawSQLJoinsTransaction(t *testing.T) {
+	db := setupTestDB(t)
+	defer func() { _ = db.Close() }()
+
+	migrator := NewMigrator(db, &SQLiteDialect{})
+	if err := migrator.Init(); err != nil {
+		t.Fatalf("Failed to initialize migrator: %v", err)
+	}
+
+	createUsers := Migration{
+		Name: "1_create_users_table",
+		Up: func() error {
+			if err := Table("users").Create(func() {
+				Integer("id").Primary()
+				String("name")
+			}); err != nil {
+				return err
+			}
+			db, _ := GetDB()
+			if _, err := db.Exec("INSERT INTO users (id, name) VALUES (?, ?)", 1, "admin"); err != nil {
+				return err
+			}
+			var name string
+			return db.QueryRow("SELECT name FROM users WHERE id = ?", 1).Scan(&name)
+		},
+		Down: func() error { return Table("users").Drop() },
+	}
+	if err := migrator.Migrate([]Migration{createUsers}); err != nil {
+		t.Fatalf("Failed to run migration: %v", err)
+	}
+
+	var count int
+	if err := db.QueryRow("SELECT COUNT(*) FROM users").Scan(&count); err != nil || count != 1 {
+		t.Fatalf("Expected the inserted user to be committed, got %d: %v", count, err)
+	}
+
+	seedUsers := Migration{
+		Name: "2_seed_users",
+		Up: func() error {
+			db, _ := GetDB()
+			_, err := db.Exec("INSERT INTO users (id, name) VALUES (?, ?)", 2, "guest")
+			return err
+		},
+		Down: func() error { return nil },
+	}
+	plans, err := migrator.Pretend([]Migration{createUsers, seedUsers})
+	if err != nil {
+		t.Fatalf("Failed to pretend: %v", err)
+	}
+	if ops := plans[0].Operations; len(ops) != 1 || ops[0].Kind != OpRawSQL {
+		t.Errorf("Expected the INSERT to be recorded, got %+v", ops)
+	}
+	if err := db.QueryRow("SELECT COUNT(*) FROM users").Scan(&count); err != nil || count != 1 {
+		t.Errorf("Expected pretend mode not to insert, got %d users: %v", count, err)
+	}
+}
+
+// statementLog is a database driver that accepts every statement and keeps
+// a log of them, for checking the statements sent to databases that are not
+// available in tests.
+type statementLog struct {
+	mu         sync.Mutex
+	statements []string
+	// rows maps part of a query to the single-column rows it returns.
+	rows map[string][]driver.Value
+}
+
+func (l *statementLog) Open(string) (driver.Conn, error) {
+	return &statementConn{l}, nil
+}
+
+func (l *statementLog) add(query string, args []driver.Value) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if len(args) > 0 {
+		query += fmt.Sprint(" ", args)
+	}
+	l.statements = append(l.statements, query)
+}
+
+func (l *statementLog) index(statement string) int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for i, s := range l.statements {
+		if strings.HasPrefix(s, statement) {
+			return i
+		}
+	}
+	return -1
+}
+
+type statementConn struct {
+	log *statementLog
+}
+
+func (c *statementConn) Prepare(query string) (driver.Stmt, error) {
+	return &statementStmt{log: c.log, query: query}, nil
+}
+
+func (c *statementConn) Close() error {
+	return nil
+}
+
+func (c *statementConn) Begin() (driver.Tx, error) {
+	c.log.add("BEGIN", nil)
+	return &statementTx{c.log}, nil
+}
+
+type statementTx struct {
+	log *statementLog
+}
+
+func (t *statementTx) Commit() error {
+	t.log.add("COMMIT", nil)
+	return nil
+}
+
+func (t *statementTx) Rollback() error {
+	t.log.add("ROLLBACK", nil)
+	return nil
+}
+
+type statementStmt struct {
+	log   *statementLog
+	query string
+}
+
+func (s *statementStmt) Close() error {
+	return nil
+}
+
+func (s *statementStmt) NumInput() int {
+	return -1
+}
+
+func (s *statementStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.log.add(strings.Join(strings.Fields(s.query), " "), args)
+	return driver.RowsAffected(1), nil
+}
+
+func (s *statementStmt) Query(args []driver.Value) (driver.Rows, error) {
+	s.log.add(strings.Join(strings.Fields(s.query), " "), args)
+	for part, values := range s.log.rows {
+		if strings.Contains(s.query, part) {
+			return &statementRows{values: append([]driver.Value(nil), values...)}, nil
+		}
+	}
+	switch {
+	case strings.Contains(s.query, "current_setting('search_path')"):
+		return &statementRows{values: []driver.Value{`"$user", public`}}, nil
+	case strings.Contains(s.query, "MAX(batch)"):
+		return &statementRows{values: []driver.Value{nil}}, nil
+	}
+	return &statementRows{}, nil
+}
+
+type statementRows struct {
+	values []driver.Value
+}
+
+func (r *statementRows) Columns() []string {
+	return []string{"value"}
+}
+
+func (r *statementRows) Close() error {
+	return nil
+}
+
+func (r *statementRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	dest[0], r.values = r.values[0], r.values[1:]
+	return nil
+}
+
+var statements = &statementLog{}
+
+func init() {
+	sql.Register("olympian-statements", statements)
+}
+
+func TestMigratorSearchPath(t *testing.T) {
+	db, err := sql.Open("olympian-statements", "")
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	defer func() { _ = db.Close() }()
+	statements.statements, statements.rows = nil, nil
+
+	migrator := NewMigrator(db, &PostgresDialect{}).SearchPath("billing, public")
+	if err := migrator.Init(); err != nil {
+		t.Fatalf("Failed to initialize migrator: %v", err)
+	}
+	err = migrator.Migrate([]Migration{
+		{
+			Name: "1_create_invoices_table",
+			Up: func() error {
+				return Table("invoices").Create(func() {
+					Integer("id").Primary()
+				})
+			},
+		},
+		{
+			Name: "2_index_invoices_table",
+			Up: func() error {
+				return CreateIndex("invoices", []string{"id"}, "invoices_id_index", Concurrently())
+			},
+			NonTransactional: true,
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to run migrations: %v", err)
+	}
+
+	order := []string{
+		"SELECT set_config('search_path', $1, $2) [billing, public true]",
+		"CREATE TABLE IF NOT EXISTS invoices",
+		`SELECT set_config('search_path', $1, $2) ["$user", public true]`,
+		"INSERT INTO olympian_migrations",
+		"COMMIT",
+		"SELECT set_config('search_path', $1, $2) [billing, public false]",
+		"CREATE INDEX CONCURRENTLY invoices_id_index",
+		`SELECT set_config('search_path', $1, $2) ["$user", public false]`,
+	}
+	last := -1
+	for _, statement := range order {
+		i := statements.index(statement)
+		if i <= last {
+			t.Fatalf("Expected %q after the previous statements, got:\n%s", statement, strings.Join(statements.statements, "\n"))
+		}
+		last = i
+	}
+}
+
+func TestFreshKeepsOtherSchemas(t *testing.T) {
+	db, err := sql.Open("olympian-statements", "")
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	tables := []driver.Value{"invoices", "olympian_migrations", "audit.events", "public.olympian_migrations", "public.users"}
+	tests := []struct {
+		searchPath []string
+		dropped    []string
+	}{
+		{nil, []string{"invoices"}},
+		{[]string{"billing", "public"}, []string{"invoices", "public.users"}},
+	}
+
+	for _, tt := range tests {
+		statements.statements = nil
+		statements.rows = map[string][]driver.Value{"information_schema.tables": tables}
+
+		migrator := NewMigrator(db, &PostgresDialect{}).SearchPath(tt.searchPath...)
+		if err := migrator.Fresh(nil); err != nil {
+			t.Fatalf("Failed to run fresh: %v", err)
+		}
+
+		var dropped []string
+		for _, statement := range statements.statements {
+			if table, ok := strings.CutPrefix(statement, "DROP TABLE IF EXISTS "); ok {
+				dropped = append(dropped, strings.TrimSuffix(table, " CASCADE"))
+			}
+		}
+		if strings.Join(dropped, ",") != strings.Join(tt.dropped, ",") {
+			t.Errorf("Search path %v: expected %v to be dropped, got %v", tt.searchPath, tt.dropped, dropped)
+		}
+	}
+}
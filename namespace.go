@@ -0,0 +1,48 @@
+package olympian
+
+import (
+	"fmt"
+	"strings"
+)
+
+// splitTableName splits billing.invoices into its schema and table. The
+// schema is empty for unqualified names.
+func splitTableName(name string) (string, string) {
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		return name[:i], name[i+1:]
+	}
+	return "", name
+}
+
+// Schema puts the table in a PostgreSQL schema, the same as
+// Table("schema.table").
+func (tb *TableBuilder) Schema(schema string) *TableBuilder {
+	_, table := splitTableName(tb.tableName)
+	tb.tableName = schema + "." + table
+	return tb
+}
+
+// CreateSchema creates a schema unless it exists. On MySQL a schema is a
+// database.
+func CreateSchema(name string) error {
+	s := current()
+	if _, isSQLite := s.dialect.(*SQLiteDialect); isSQLite {
+		return fmt.Errorf("schema %s: SQLite does not support schemas, attach a database instead", name)
+	}
+
+	query := fmt.Sprintf("CREATE SCHEMA IF NOT EXISTS %s", s.dialect.QuoteIdentifier(name))
+	return s.execute(Operation{Kind: OpCreateSchema, SQL: []string{query}})
+}
+
+// DropSchema drops a schema if it exists. PostgreSQL refuses to drop a
+// schema that still contains objects; MySQL drops the database with all its
+// tables.
+func DropSchema(name string) error {
+	s := current()
+	if _, isSQLite := s.dialect.(*SQLiteDialect); isSQLite {
+		return fmt.Errorf("schema %s: SQLite does not support schemas, attach a database instead", name)
+	}
+
+	query := fmt.Sprintf("DROP SCHEMA IF EXISTS %s", s.dialect.QuoteIdentifier(name))
+	return s.execute(Operation{Kind: OpDropSchema, SQL: []string{query}})
+}
@@ -0,0 +1,82 @@
+package olympian
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSchemaQualifiedTableSQL(t *testing.T) {
+	tb := Table("invoices").Schema("billing")
+	if tb.tableName != "billing.invoices" {
+		t.Fatalf("Expected billing.invoices, got %s", tb.tableName)
+	}
+
+	tb.columns = []*Column{
+		{name: "id", dataType: "integer", primary: true},
+		{name: "customer_id", dataType: "integer", index: true},
+		{name: "status", dataType: "enum", enumValues: []string{"draft", "paid"}, enumType: enumTypeName(tb.tableName, "status")},
+	}
+	tb.foreignKeys = []*ForeignKey{{columns: []string{"customer_id"}, refTable: "billing.customers", refColumns: []string{"id"}}}
+
+	expected := `DO $enum$ BEGIN CREATE TYPE billing.invoices_status_enum AS ENUM ('draft', 'paid'); EXCEPTION WHEN duplicate_object THEN NULL; END $enum$;
+CREATE TABLE IF NOT EXISTS billing.invoices (
+  id INTEGER PRIMARY KEY NOT NULL,
+  customer_id INTEGER NOT NULL,
+  status billing.invoices_status_enum NOT NULL,
+  CONSTRAINT fk_invoices_customer_id FOREIGN KEY (customer_id) REFERENCES billing.customers(id)
+);;
+CREATE INDEX invoices_customer_id_index ON billing.invoices (customer_id)`
+	if sql := buildCreateTable(t, &PostgresDialect{}, tb); sql != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, sql)
+	}
+
+	tests := []struct {
+		dialect  Dialect
+		expected string
+	}{
+		{&PostgresDialect{}, "DROP INDEX IF EXISTS billing.invoices_customer_id_index"},
+		{&PostgresDialect{AlwaysQuote: true}, `DROP INDEX IF EXISTS "billing"."invoices_customer_id_index"`},
+		{&MySQLDialect{}, "DROP INDEX invoices_customer_id_index ON billing.invoices"},
+	}
+	for _, tt := range tests {
+		if sql := dropIndexSQL(tt.dialect, "billing.invoices", "invoices_customer_id_index"); sql != tt.expected {
+			t.Errorf("%T: expected %s, got %s", tt.dialect, tt.expected, sql)
+		}
+	}
+}
+
+func TestCreateAndDropSchemaSQL(t *testing.T) {
+	for _, tt := range []struct {
+		dialect  Dialect
+		expected []string
+	}{
+		{&PostgresDialect{}, []string{"CREATE SCHEMA IF NOT EXISTS billing", `DROP SCHEMA IF EXISTS "order"`}},
+		{&MySQLDialect{}, []string{"CREATE SCHEMA IF NOT EXISTS billing", "DROP SCHEMA IF EXISTS `order`"}},
+	} {
+		SetDB(nil, tt.dialect)
+		var ops []Operation
+		recorder = &ops
+		createErr := CreateSchema("billing")
+		dropErr := DropSchema("order")
+		recorder = nil
+		if createErr != nil || dropErr != nil {
+			t.Fatalf("%T: failed to build schema operations: %v, %v", tt.dialect, createErr, dropErr)
+		}
+
+		var sqls []string
+		for _, op := range ops {
+			sqls = append(sqls, op.SQL...)
+		}
+		if strings.Join(sqls, "\n") != strings.Join(tt.expected, "\n") {
+			t.Errorf("%T: expected %v, got %v", tt.dialect, tt.expected, sqls)
+		}
+		if ops[0].Kind != OpCreateSchema || ops[1].Kind != OpDropSchema {
+			t.Errorf("%T: unexpected operation kinds %s and %s", tt.dialect, ops[0].Kind, ops[1].Kind)
+		}
+	}
+
+	SetDB(nil, &SQLiteDialect{})
+	if err := CreateSchema("billing"); err == nil {
+		t.Error("Expected an error for schemas on SQLite")
+	}
+}
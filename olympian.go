@@ -3,11 +3,14 @@ package olympian
 import (
 	"database/sql"
 	"fmt"
+	"strings"
 	"sync"
 )
 
 var (
 	globalDB      *sql.DB
+	globalTx      *sql.Tx
+	globalScope   *sql.DB
 	globalDialect Dialect
 	mu            sync.RWMutex
 )
@@ -19,9 +22,54 @@ func SetDB(db *sql.DB, dialect Dialect) {
 	globalDialect = dialect
 }
 
+// GetDB returns the database set with SetDB. Inside a migration that runs in
+// a transaction, statements on the returned database join that transaction,
+// and in pretend mode they are recorded instead of executed.
 func GetDB() (*sql.DB, Dialect) {
 	mu.RLock()
 	defer mu.RUnlock()
+	if globalScope != nil {
+		return globalScope, globalDialect
+	}
+	return globalDB, globalDialect
+}
+
+// currentDB returns the database set with SetDB, ignoring the scope of a
+// running migration.
+func currentDB() (*sql.DB, Dialect) {
+	mu.RLock()
+	defer mu.RUnlock()
+	return globalDB, globalDialect
+}
+
+func setTx(tx *sql.Tx) {
+	mu.Lock()
+	defer mu.Unlock()
+	globalTx = tx
+	if tx != nil {
+		setScope(scopedDB(tx, nil))
+	} else {
+		setScope(nil)
+	}
+}
+
+// setScope replaces the database GetDB returns while a migration runs. The
+// caller holds mu.
+func setScope(db *sql.DB) {
+	if globalScope != nil {
+		_ = globalScope.Close()
+	}
+	globalScope = db
+}
+
+// conn returns the transaction of the running migration, if there is one, or
+// the database set with SetDB.
+func conn() (Querier, Dialect) {
+	mu.RLock()
+	defer mu.RUnlock()
+	if globalTx != nil {
+		return globalTx, globalDialect
+	}
 	return globalDB, globalDialect
 }
 
@@ -29,72 +77,374 @@ type Migration struct {
 	Name string
 	Up   func() error
 	Down func() error
+
+	// NonTransactional runs the migration outside a transaction. It is needed
+	// for statements Postgres refuses inside one, such as CREATE INDEX
+	// CONCURRENTLY.
+	NonTransactional bool
 }
 
 type TableBuilder struct {
+	scope
+
 	tableName   string
 	columns     []*Column
 	operation   string
-	dialect     Dialect
-	db          *sql.DB
 	foreignKeys []*ForeignKey
+	indexes     []*tableIndex
+	checks      []*tableCheck
+	primaryKey  []string
+
+	droppedForeignKeys []string
+	droppedChecks      []string
+	algorithm          string
+	lock               string
+	comment            string
+	engine             string
+	charset            string
+	collation          string
+	rowFormat          string
 }
 
 type Column struct {
 	name          string
 	dataType      string
+	length        int
 	nullable      bool
 	primary       bool
 	unique        bool
-	defaultValue  *string
+	index         bool
+	check         string
+	comment       string
+	charset       string
+	collation     string
+	generatedAs   string
+	virtual       bool
+	enumValues    []string
+	enumType      string
+	defaultValue  interface{}
 	afterColumn   *string
 	autoIncrement bool
+	nullableSet   bool
+	change        bool
+	existing      *ColumnInfo
 }
 
 type ForeignKey struct {
-	column    string
-	refTable  string
-	refColumn string
-	onDelete  string
-	onUpdate  string
+	columns    []string
+	refTable   string
+	refColumns []string
+	onDelete   string
+	onUpdate   string
+	name       string
+	deferrable bool
+}
+
+// constraintName returns the custom name or fk_<table>_<columns>.
+func (fk *ForeignKey) constraintName(tableName string) string {
+	if fk.name != "" {
+		return fk.name
+	}
+	return foreignKeyName(tableName, fk.columns)
 }
 
+func foreignKeyName(tableName string, columns []string) string {
+	_, table := splitTableName(tableName)
+	return fmt.Sprintf("fk_%s_%s", table, strings.Join(columns, "_"))
+}
+
+// Table starts building a table on the running migration, or on the
+// database set with SetDB. The connection is captured here, so the builder
+// keeps using it even if another migration starts before it runs.
 func Table(name string) *TableBuilder {
-	db, dialect := GetDB()
+	return newTableBuilder(current(), name)
+}
+
+// TableOn starts building a table on db, independent of SetDB and of any
+// running migration. Together with CreateWith and ModifyWith it is safe to
+// use from several goroutines, for example in parallel tests.
+func TableOn(db Querier, dialect Dialect, name string) *TableBuilder {
+	_, tx := db.(*sql.Tx)
+	return newTableBuilder(scope{db: db, dialect: dialect, tx: tx}, name)
+}
+
+func newTableBuilder(s scope, name string) *TableBuilder {
 	return &TableBuilder{
+		scope:       s,
 		tableName:   name,
 		columns:     make([]*Column, 0),
-		dialect:     dialect,
-		db:          db,
 		foreignKeys: make([]*ForeignKey, 0),
 	}
 }
 
+// Create builds the table with the package-level column helpers. Use
+// CreateWith when tables are built from several goroutines.
 func (tb *TableBuilder) Create(fn func()) error {
+	return tb.create(shared(fn))
+}
+
+// CreateWith builds the table through a Blueprint, which keeps all state
+// local and is safe for concurrent use.
+func (tb *TableBuilder) CreateWith(fn func(*Blueprint)) error {
+	return tb.create(fn)
+}
+
+func (tb *TableBuilder) create(fn func(*Blueprint)) error {
 	tb.operation = "create"
-	currentBuilder = tb
-	fn()
+	tb.build(fn)
+	if err := tb.validatePrimaryKey(); err != nil {
+		return err
+	}
+	if err := tb.validateForeignKeys(); err != nil {
+		return err
+	}
+	if err := tb.validateIndexes(); err != nil {
+		return err
+	}
+	if err := tb.validateChecks(); err != nil {
+		return err
+	}
+	if err := tb.validateEnums(); err != nil {
+		return err
+	}
+	if err := tb.validateTableOptions(); err != nil {
+		return err
+	}
+	if err := tb.validateGeneratedColumns(); err != nil {
+		return err
+	}
+
+	sqls, err := tb.dialect.BuildCreateTable(tb)
+	if err != nil {
+		return err
+	}
+
+	return tb.execute(Operation{
+		Kind:    OpCreateTable,
+		Table:   tb.tableName,
+		indexes: indexNames(tb.tableIndexes()),
+		SQL:     sqls,
+		columns: tb.columns,
+	})
+}
+
+// Algorithm sets ALGORITHM=INSTANT|INPLACE|COPY on the ALTER TABLE statements
+// of Modify and DropColumn. It only applies to MySQL.
+func (tb *TableBuilder) Algorithm(algorithm string) *TableBuilder {
+	tb.algorithm = strings.ToUpper(algorithm)
+	return tb
+}
+
+// Lock sets LOCK=NONE|SHARED|EXCLUSIVE on the ALTER TABLE statements of
+// Modify and DropColumn. It only applies to MySQL.
+func (tb *TableBuilder) Lock(lock string) *TableBuilder {
+	tb.lock = strings.ToUpper(lock)
+	return tb
+}
 
-	query := tb.dialect.BuildCreateTable(tb)
-	_, err := tb.db.Exec(query)
-	return err
+func (tb *TableBuilder) validateAlterOptions() error {
+	switch tb.algorithm {
+	case "", "DEFAULT", "INSTANT", "INPLACE", "COPY":
+	default:
+		return fmt.Errorf("table %s: unsupported algorithm %q, expected INSTANT, INPLACE or COPY", tb.tableName, tb.algorithm)
+	}
+	switch tb.lock {
+	case "", "DEFAULT", "NONE", "SHARED", "EXCLUSIVE":
+	default:
+		return fmt.Errorf("table %s: unsupported lock %q, expected NONE, SHARED or EXCLUSIVE", tb.tableName, tb.lock)
+	}
+	return nil
 }
 
+// Modify alters the table with the package-level column helpers. Use
+// ModifyWith when tables are built from several goroutines.
 func (tb *TableBuilder) Modify(fn func()) error {
+	return tb.modify(shared(fn))
+}
+
+// ModifyWith alters the table through a Blueprint, which is safe for
+// concurrent use.
+func (tb *TableBuilder) ModifyWith(fn func(*Blueprint)) error {
+	return tb.modify(fn)
+}
+
+func (tb *TableBuilder) modify(fn func(*Blueprint)) error {
 	tb.operation = "modify"
-	currentBuilder = tb
-	fn()
+	if err := tb.validateAlterOptions(); err != nil {
+		return err
+	}
+	tb.build(fn)
+	if len(tb.primaryKey) > 0 {
+		return fmt.Errorf("table %s: PrimaryKey is only supported when creating a table", tb.tableName)
+	}
+	if err := tb.validateForeignKeys(); err != nil {
+		return err
+	}
+	if err := tb.validateIndexes(); err != nil {
+		return err
+	}
+	if err := tb.validateChecks(); err != nil {
+		return err
+	}
+	if err := tb.validateEnums(); err != nil {
+		return err
+	}
+	if err := tb.validateTableOptions(); err != nil {
+		return err
+	}
+	if err := tb.validateGeneratedColumns(); err != nil {
+		return err
+	}
 
-	sqls := tb.dialect.BuildModifyTable(tb)
-	for _, query := range sqls {
-		if _, err := tb.db.Exec(query); err != nil {
+	kind := OpModifyTable
+	for _, col := range tb.columns {
+		if col.change {
+			kind = OpChangeColumn
+		}
+	}
+	if kind == OpChangeColumn {
+		if err := tb.resolveChanges(); err != nil {
 			return err
 		}
 	}
+
+	sqls, err := tb.dialect.BuildModifyTable(tb)
+	if err != nil {
+		return err
+	}
+
+	op := Operation{
+		Kind:    kind,
+		Table:   tb.tableName,
+		SQL:     sqls,
+		columns: tb.columns,
+		indexes: indexNames(tb.tableIndexes()),
+	}
+	if d, ok := tb.dialect.(*SQLiteDialect); ok && d.needsRebuild(tb) {
+		op.exec = func(q Querier) error { return d.runRebuild(q, sqls) }
+	}
+	return tb.execute(op)
+}
+
+// validatePrimaryKey rejects tables with more than one primary key: several
+// inline Primary() columns, or PrimaryKey combined with an inline Primary().
+func (tb *TableBuilder) validatePrimaryKey() error {
+	var inline []string
+	for _, col := range tb.columns {
+		if col.primary {
+			inline = append(inline, col.name)
+		}
+	}
+
+	if len(tb.primaryKey) == 0 {
+		if len(inline) > 1 {
+			return fmt.Errorf("table %s: columns %s are all marked Primary(), use PrimaryKey(%s) for a composite key",
+				tb.tableName, joinColumns(inline), joinColumns(inline))
+		}
+		return nil
+	}
+
+	if len(inline) > 0 {
+		return fmt.Errorf("table %s: column %s is marked Primary() but the table also has PrimaryKey(%s)",
+			tb.tableName, inline[0], joinColumns(tb.primaryKey))
+	}
+	for _, name := range tb.primaryKey {
+		found := false
+		for _, col := range tb.columns {
+			if col.name == name {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("table %s: primary key column %s is not defined", tb.tableName, name)
+		}
+	}
+	return nil
+}
+
+func (tb *TableBuilder) validateForeignKeys() error {
+	_, isMySQL := tb.dialect.(*MySQLDialect)
+	for _, fk := range tb.foreignKeys {
+		name := fk.constraintName(tb.tableName)
+		switch {
+		case len(fk.columns) == 0:
+			return fmt.Errorf("foreign key %s has no columns", name)
+		case fk.refTable == "":
+			return fmt.Errorf("foreign key %s has no referenced table, use On(table)", name)
+		case len(fk.refColumns) != len(fk.columns):
+			return fmt.Errorf("foreign key %s references %d columns for %d columns", name, len(fk.refColumns), len(fk.columns))
+		case fk.deferrable && isMySQL:
+			return fmt.Errorf("foreign key %s: MySQL does not support deferrable constraints", name)
+		}
+	}
 	return nil
 }
 
+// resolveChanges looks up the current definition of every changed column so
+// the dialects can keep the modifiers that were not set explicitly.
+func (tb *TableBuilder) resolveChanges() error {
+	if tb.plannedTable(tb.tableName) {
+		return nil
+	}
+	columns, err := tb.dialect.GetColumns(tb.db, tb.tableName)
+	if err != nil {
+		return fmt.Errorf("failed to inspect table %s: %w", tb.tableName, err)
+	}
+	table := &TableInfo{Name: tb.tableName, Columns: columns}
+
+	for _, col := range tb.columns {
+		if !col.change {
+			continue
+		}
+		existing, ok := table.Column(col.name)
+		if !ok {
+			return fmt.Errorf("cannot change column %s.%s: column does not exist", tb.tableName, col.name)
+		}
+		col.existing = &existing
+		if !col.nullableSet {
+			col.nullable = existing.Nullable
+		}
+	}
+	return nil
+}
+
+func (tb *TableBuilder) build(fn func(*Blueprint)) {
+	fn(&Blueprint{tb: tb})
+}
+
+// shared adapts a callback using the package-level column helpers, which
+// write to currentBuilder. builderMu keeps two such callbacks from running at
+// once; the helpers must only be called from inside the callback.
+func shared(fn func()) func(*Blueprint) {
+	return func(bp *Blueprint) {
+		builderMu.Lock()
+		defer builderMu.Unlock()
+
+		currentBuilder = bp.tb
+		defer func() { currentBuilder = nil }()
+		fn()
+	}
+}
+
 func (tb *TableBuilder) Drop() error {
+	sqls := []string{tb.dialect.BuildDropTable(tb.tableName)}
+
+	// Enum types created for the table's columns go with it.
+	if _, isPostgres := tb.dialect.(*PostgresDialect); isPostgres {
+		types, err := postgresEnumTypes(tb.db, tb.tableName)
+		if err != nil {
+			return err
+		}
+		for _, name := range types {
+			sqls = append(sqls, fmt.Sprintf("DROP TYPE IF EXISTS %s", tb.dialect.QuoteIdentifier(name)))
+		}
+	}
+
+	if tb.pretending() {
+		return tb.execute(Operation{Kind: OpDropTable, Table: tb.tableName, SQL: sqls})
+	}
+
 	// For MySQL, disable foreign key checks temporarily
 	if _, isMySQL := tb.dialect.(*MySQLDialect); isMySQL {
 		if _, err := tb.db.Exec("SET FOREIGN_KEY_CHECKS = 0"); err != nil {
@@ -105,141 +455,225 @@ func (tb *TableBuilder) Drop() error {
 		}()
 	}
 
-	query := tb.dialect.BuildDropTable(tb.tableName)
-	_, err := tb.db.Exec(query)
-	return err
+	for _, query := range sqls {
+		if _, err := tb.db.Exec(query); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func (tb *TableBuilder) DropColumn(columnName string) error {
-	query := tb.dialect.BuildDropColumn(tb.tableName, columnName)
-	_, err := tb.db.Exec(query)
-	return err
+	if err := tb.validateAlterOptions(); err != nil {
+		return err
+	}
+
+	op := Operation{
+		Kind:   OpDropColumn,
+		Table:  tb.tableName,
+		SQL:    []string{tb.dialect.BuildDropColumn(tb.tableName, columnName)},
+		column: columnName,
+	}
+
+	switch d := tb.dialect.(type) {
+	case *MySQLDialect:
+		op.SQL[0] += mysqlAlterOptions(tb)
+	case *SQLiteDialect:
+		rebuild, err := d.dropNeedsRebuild(tb, columnName)
+		if err != nil {
+			return err
+		}
+		if rebuild {
+			sqls, err := d.rebuildTable(tb, []string{columnName})
+			if err != nil {
+				return err
+			}
+			op.SQL = sqls
+			op.exec = func(q Querier) error { return d.runRebuild(q, sqls) }
+		}
+	}
+
+	return tb.execute(op)
+}
+
+// DropForeign drops a foreign key constraint by name. Constraints declared
+// without Name are called fk_<table>_<columns>.
+func (tb *TableBuilder) DropForeign(name string) error {
+	op := Operation{Kind: OpDropForeign, Table: tb.tableName}
+
+	switch d := tb.dialect.(type) {
+	case *PostgresDialect:
+		op.SQL = []string{fmt.Sprintf("ALTER TABLE %s DROP CONSTRAINT %s", d.QuoteIdentifier(tb.tableName), d.QuoteIdentifier(name))}
+	case *MySQLDialect:
+		op.SQL = []string{fmt.Sprintf("ALTER TABLE %s DROP FOREIGN KEY %s", d.QuoteIdentifier(tb.tableName), d.QuoteIdentifier(name)) + mysqlAlterOptions(tb)}
+	case *SQLiteDialect:
+		tb.droppedForeignKeys = append(tb.droppedForeignKeys, name)
+		sqls, err := d.rebuildTable(tb, nil)
+		if err != nil {
+			return err
+		}
+		op.SQL = sqls
+		op.exec = func(q Querier) error { return d.runRebuild(q, sqls) }
+	}
+
+	return tb.execute(op)
 }
 
-var currentBuilder *TableBuilder
+var (
+	currentBuilder *TableBuilder
+	builderMu      sync.Mutex
+)
 
 type ColumnBuilder struct {
 	column *Column
 }
 
-func Uuid(name string) *ColumnBuilder {
+func (tb *TableBuilder) addColumn(name, dataType string) *ColumnBuilder {
 	col := &Column{
 		name:     name,
-		dataType: "uuid",
+		dataType: dataType,
 		nullable: false,
 	}
-	if currentBuilder != nil {
-		currentBuilder.columns = append(currentBuilder.columns, col)
+	if tb != nil {
+		tb.columns = append(tb.columns, col)
 	}
 	return &ColumnBuilder{column: col}
 }
 
-func String(name string) *ColumnBuilder {
-	col := &Column{
-		name:     name,
-		dataType: "string",
-		nullable: false,
+func (tb *TableBuilder) addString(name, dataType string, length []int) *ColumnBuilder {
+	cb := tb.addColumn(name, dataType)
+	if len(length) > 0 {
+		cb.column.length = length[0]
 	}
-	if currentBuilder != nil {
-		currentBuilder.columns = append(currentBuilder.columns, col)
+	return cb
+}
+
+func (tb *TableBuilder) setPrimaryKey(columns []string) {
+	if tb != nil {
+		tb.primaryKey = columns
 	}
-	return &ColumnBuilder{column: col}
 }
 
-func Text(name string) *ColumnBuilder {
-	col := &Column{
-		name:     name,
-		dataType: "text",
-		nullable: false,
+func (tb *TableBuilder) addForeign(columns []string) *ForeignKeyBuilder {
+	fk := &ForeignKey{
+		columns: columns,
 	}
-	if currentBuilder != nil {
-		currentBuilder.columns = append(currentBuilder.columns, col)
+	if tb != nil {
+		tb.foreignKeys = append(tb.foreignKeys, fk)
 	}
-	return &ColumnBuilder{column: col}
+	return &ForeignKeyBuilder{fk: fk}
+}
+
+func Uuid(name string) *ColumnBuilder {
+	return currentBuilder.addColumn(name, "uuid")
+}
+
+// String adds a VARCHAR column, 255 characters long unless length is given.
+func String(name string, length ...int) *ColumnBuilder {
+	return currentBuilder.addString(name, "string", length)
+}
+
+// Char adds a fixed-length CHAR column.
+func Char(name string, length int) *ColumnBuilder {
+	return currentBuilder.addString(name, "char", []int{length})
+}
+
+func Text(name string) *ColumnBuilder {
+	return currentBuilder.addColumn(name, "text")
 }
 
 func Integer(name string) *ColumnBuilder {
-	col := &Column{
-		name:     name,
-		dataType: "integer",
-		nullable: false,
-	}
-	if currentBuilder != nil {
-		currentBuilder.columns = append(currentBuilder.columns, col)
-	}
-	return &ColumnBuilder{column: col}
+	return currentBuilder.addColumn(name, "integer")
 }
 
 func BigInteger(name string) *ColumnBuilder {
-	col := &Column{
-		name:     name,
-		dataType: "bigint",
-		nullable: false,
-	}
-	if currentBuilder != nil {
-		currentBuilder.columns = append(currentBuilder.columns, col)
-	}
-	return &ColumnBuilder{column: col}
+	return currentBuilder.addColumn(name, "bigint")
 }
 
 func Boolean(name string) *ColumnBuilder {
-	col := &Column{
-		name:     name,
-		dataType: "boolean",
-		nullable: false,
-	}
-	if currentBuilder != nil {
-		currentBuilder.columns = append(currentBuilder.columns, col)
-	}
-	return &ColumnBuilder{column: col}
+	return currentBuilder.addColumn(name, "boolean")
 }
 
 func Decimal(name string, precision, scale int) *ColumnBuilder {
-	col := &Column{
-		name:     name,
-		dataType: fmt.Sprintf("decimal(%d,%d)", precision, scale),
-		nullable: false,
-	}
-	if currentBuilder != nil {
-		currentBuilder.columns = append(currentBuilder.columns, col)
-	}
-	return &ColumnBuilder{column: col}
+	return currentBuilder.addColumn(name, fmt.Sprintf("decimal(%d,%d)", precision, scale))
 }
 
 func Timestamp(name string) *ColumnBuilder {
-	col := &Column{
-		name:     name,
-		dataType: "timestamp",
-		nullable: false,
-	}
-	if currentBuilder != nil {
-		currentBuilder.columns = append(currentBuilder.columns, col)
-	}
-	return &ColumnBuilder{column: col}
+	return currentBuilder.addColumn(name, "timestamp")
 }
 
 func Date(name string) *ColumnBuilder {
-	col := &Column{
-		name:     name,
-		dataType: "date",
-		nullable: false,
-	}
-	if currentBuilder != nil {
-		currentBuilder.columns = append(currentBuilder.columns, col)
-	}
-	return &ColumnBuilder{column: col}
+	return currentBuilder.addColumn(name, "date")
 }
 
 func Json(name string) *ColumnBuilder {
-	col := &Column{
-		name:     name,
-		dataType: "json",
-		nullable: false,
-	}
-	if currentBuilder != nil {
-		currentBuilder.columns = append(currentBuilder.columns, col)
-	}
-	return &ColumnBuilder{column: col}
+	return currentBuilder.addColumn(name, "json")
+}
+
+// Jsonb is JSONB on PostgreSQL and the same as Json elsewhere.
+func Jsonb(name string) *ColumnBuilder {
+	return currentBuilder.addColumn(name, "jsonb")
+}
+
+func SmallInteger(name string) *ColumnBuilder {
+	return currentBuilder.addColumn(name, "smallint")
+}
+
+func TinyInteger(name string) *ColumnBuilder {
+	return currentBuilder.addColumn(name, "tinyint")
+}
+
+func Float(name string) *ColumnBuilder {
+	return currentBuilder.addColumn(name, "float")
+}
+
+func Double(name string) *ColumnBuilder {
+	return currentBuilder.addColumn(name, "double")
+}
+
+func MediumText(name string) *ColumnBuilder {
+	return currentBuilder.addColumn(name, "mediumtext")
+}
+
+func LongText(name string) *ColumnBuilder {
+	return currentBuilder.addColumn(name, "longtext")
+}
+
+// Binary adds a short variable-length binary column, 255 bytes unless length
+// is given. PostgreSQL has no length limit on BYTEA.
+func Binary(name string, length ...int) *ColumnBuilder {
+	return currentBuilder.addString(name, "binary", length)
+}
+
+func Blob(name string) *ColumnBuilder {
+	return currentBuilder.addColumn(name, "blob")
+}
+
+func Time(name string) *ColumnBuilder {
+	return currentBuilder.addColumn(name, "time")
+}
+
+func DateTime(name string) *ColumnBuilder {
+	return currentBuilder.addColumn(name, "datetime")
+}
+
+func Year(name string) *ColumnBuilder {
+	return currentBuilder.addColumn(name, "year")
+}
+
+// IpAddress holds IPv4 and IPv6 addresses.
+func IpAddress(name string) *ColumnBuilder {
+	return currentBuilder.addColumn(name, "ipaddress")
+}
+
+func MacAddress(name string) *ColumnBuilder {
+	return currentBuilder.addColumn(name, "macaddress")
+}
+
+// PrimaryKey declares a primary key over one or more columns, e.g. the two
+// foreign keys of a pivot table.
+func PrimaryKey(columns ...string) {
+	currentBuilder.setPrimaryKey(columns)
 }
 
 func Timestamps() {
@@ -251,8 +685,11 @@ func SoftDeletes() {
 	Timestamp("deleted_at").Nullable()
 }
 
-func (cb *ColumnBuilder) Nullable() *ColumnBuilder {
-	cb.column.nullable = true
+// Nullable allows NULL values. Nullable(false) makes the column NOT NULL,
+// which is the default but matters when used with Change.
+func (cb *ColumnBuilder) Nullable(nullable ...bool) *ColumnBuilder {
+	cb.column.nullable = len(nullable) == 0 || nullable[0]
+	cb.column.nullableSet = true
 	return cb
 }
 
@@ -266,9 +703,16 @@ func (cb *ColumnBuilder) Unique() *ColumnBuilder {
 	return cb
 }
 
+// Index adds an index named <table>_<column>_index on the column.
+func (cb *ColumnBuilder) Index() *ColumnBuilder {
+	cb.column.index = true
+	return cb
+}
+
+// Default sets a literal default, quoted and escaped for the database. Use
+// DefaultRaw for expressions.
 func (cb *ColumnBuilder) Default(value interface{}) *ColumnBuilder {
-	val := fmt.Sprintf("%v", value)
-	cb.column.defaultValue = &val
+	cb.column.defaultValue = value
 	return cb
 }
 
@@ -282,22 +726,26 @@ func (cb *ColumnBuilder) AutoIncrement() *ColumnBuilder {
 	return cb
 }
 
+// Change alters an existing column inside Modify instead of adding it. The
+// type is always changed; nullability and the default are only changed when
+// set explicitly and otherwise keep their current values.
+func (cb *ColumnBuilder) Change() *ColumnBuilder {
+	cb.column.change = true
+	return cb
+}
+
 type ForeignKeyBuilder struct {
 	fk *ForeignKey
 }
 
-func Foreign(columnName string) *ForeignKeyBuilder {
-	fk := &ForeignKey{
-		column: columnName,
-	}
-	if currentBuilder != nil {
-		currentBuilder.foreignKeys = append(currentBuilder.foreignKeys, fk)
-	}
-	return &ForeignKeyBuilder{fk: fk}
+// Foreign declares a foreign key over one or more columns. Inside Modify it
+// is added to the existing table.
+func Foreign(columns ...string) *ForeignKeyBuilder {
+	return currentBuilder.addForeign(columns)
 }
 
-func (fkb *ForeignKeyBuilder) References(column string) *ForeignKeyBuilder {
-	fkb.fk.refColumn = column
+func (fkb *ForeignKeyBuilder) References(columns ...string) *ForeignKeyBuilder {
+	fkb.fk.refColumns = columns
 	return fkb
 }
 
@@ -315,3 +763,16 @@ func (fkb *ForeignKeyBuilder) OnUpdate(action string) *ForeignKeyBuilder {
 	fkb.fk.onUpdate = action
 	return fkb
 }
+
+// Name overrides the default constraint name fk_<table>_<columns>.
+func (fkb *ForeignKeyBuilder) Name(name string) *ForeignKeyBuilder {
+	fkb.fk.name = name
+	return fkb
+}
+
+// DeferrableInitiallyDeferred checks the constraint at commit instead of
+// after each statement. MySQL does not support deferred constraints.
+func (fkb *ForeignKeyBuilder) DeferrableInitiallyDeferred() *ForeignKeyBuilder {
+	fkb.fk.deferrable = true
+	return fkb
+}
@@ -218,6 +218,58 @@ func TestUniqueConstraint(t *testing.T) {
 	}
 }
 
+func TestCompositePrimaryKey(t *testing.T) {
+	db := setupTestDB(t)
+	defer func() { _ = db.Close() }()
+
+	SetDB(db, &SQLiteDialect{})
+
+	err := Table("role_user").Create(func() {
+		Integer("user_id")
+		Integer("role_id")
+		PrimaryKey("user_id", "role_id")
+	})
+	if err != nil {
+		t.Fatalf("Failed to create table with composite primary key: %v", err)
+	}
+
+	if _, err := db.Exec("INSERT INTO role_user (user_id, role_id) VALUES (1, 1), (1, 2)"); err != nil {
+		t.Fatalf("Failed to insert rows: %v", err)
+	}
+	if _, err := db.Exec("INSERT INTO role_user (user_id, role_id) VALUES (1, 2)"); err == nil {
+		t.Error("Expected a duplicate composite key to be rejected")
+	}
+}
+
+func TestPrimaryKeyValidation(t *testing.T) {
+	db := setupTestDB(t)
+	defer func() { _ = db.Close() }()
+
+	SetDB(db, &SQLiteDialect{})
+
+	tests := map[string]func(){
+		"several inline primaries": func() {
+			Integer("user_id").Primary()
+			Integer("role_id").Primary()
+		},
+		"inline and table primary key": func() {
+			Integer("user_id").Primary()
+			Integer("role_id")
+			PrimaryKey("user_id", "role_id")
+		},
+		"unknown column": func() {
+			Integer("user_id")
+			PrimaryKey("user_id", "role_id")
+		},
+	}
+
+	for name, fn := range tests {
+		if err := Table("role_user").Create(fn); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
 func TestPostgresDialect(t *testing.T) {
 	dialect := &PostgresDialect{}
 
@@ -230,7 +282,7 @@ func TestPostgresDialect(t *testing.T) {
 		},
 	}
 
-	sql := dialect.BuildCreateTable(tb)
+	sql := buildCreateTable(t, dialect, tb)
 	if sql == "" {
 		t.Error("PostgreSQL dialect failed to build CREATE TABLE")
 	}
@@ -251,7 +303,7 @@ func TestMySQLDialect(t *testing.T) {
 		},
 	}
 
-	sql := dialect.BuildCreateTable(tb)
+	sql := buildCreateTable(t, dialect, tb)
 	if sql == "" {
 		t.Error("MySQL dialect failed to build CREATE TABLE")
 	}
@@ -276,7 +328,7 @@ func TestSQLiteDialect(t *testing.T) {
 		},
 	}
 
-	sql := dialect.BuildCreateTable(tb)
+	sql := buildCreateTable(t, dialect, tb)
 	if sql == "" {
 		t.Error("SQLite dialect failed to build CREATE TABLE")
 	}
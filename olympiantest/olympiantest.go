@@ -0,0 +1,298 @@
+package olympiantest
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/ichtrojan/olympian"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+type NotReversibleError struct {
+	Migration string
+	Missing   []string
+	Extra     []string
+}
+
+func (e *NotReversibleError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "migration %s is not reversible: schema after Down differs from schema before Up", e.Migration)
+	for _, line := range e.Missing {
+		fmt.Fprintf(&b, "\n  - %s", line)
+	}
+	for _, line := range e.Extra {
+		fmt.Fprintf(&b, "\n  + %s", line)
+	}
+	return b.String()
+}
+
+// AssertReversible fails the test at the first migration whose Down does not
+// restore the schema that existed before its Up.
+func AssertReversible(t testing.TB, migrations []olympian.Migration, dialect olympian.Dialect) {
+	t.Helper()
+	if err := CheckReversible(migrations, dialect); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// CheckReversible applies each migration to a temporary SQLite database,
+// rolls it back, compares the schema with the pre-migration snapshot and
+// re-applies it before moving on to the next one. dialect must be nil or
+// SQLite; migrations are never run against other databases.
+func CheckReversible(migrations []olympian.Migration, dialect olympian.Dialect) error {
+	if dialect == nil {
+		dialect = olympian.SQLite()
+	}
+	if _, ok := dialect.(*olympian.SQLiteDialect); !ok {
+		return fmt.Errorf("olympiantest: only SQLite is supported, got %T", dialect)
+	}
+
+	dir, err := os.MkdirTemp("", "olympiantest")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	db, err := sql.Open("sqlite3", filepath.Join(dir, "olympiantest.db"))
+	if err != nil {
+		return fmt.Errorf("failed to open temporary database: %w", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	migrator := olympian.NewMigrator(db, dialect)
+	if err := migrator.Init(); err != nil {
+		return fmt.Errorf("failed to initialize migrator: %w", err)
+	}
+
+	sorted := append([]olympian.Migration(nil), migrations...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Name < sorted[j].Name
+	})
+
+	for _, migration := range sorted {
+		before, err := snapshot(db, dialect)
+		if err != nil {
+			return err
+		}
+
+		if err := migrator.Migrate([]olympian.Migration{migration}); err != nil {
+			return fmt.Errorf("migration %s: up failed: %w", migration.Name, err)
+		}
+
+		if err := migrator.Rollback(sorted, 1); err != nil {
+			return fmt.Errorf("migration %s: down failed: %w", migration.Name, err)
+		}
+
+		after, err := snapshot(db, dialect)
+		if err != nil {
+			return err
+		}
+
+		if missing, extra := compare(before, after); len(missing) > 0 || len(extra) > 0 {
+			return &NotReversibleError{
+				Migration: migration.Name,
+				Missing:   missing,
+				Extra:     extra,
+			}
+		}
+
+		if err := migrator.Migrate([]olympian.Migration{migration}); err != nil {
+			return fmt.Errorf("migration %s: up failed after rollback: %w", migration.Name, err)
+		}
+	}
+
+	return nil
+}
+
+func snapshot(db *sql.DB, dialect olympian.Dialect) ([]string, error) {
+	tables, err := dialect.GetTables(db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to snapshot tables: %w", err)
+	}
+
+	var lines []string
+	for _, table := range tables {
+		if table == "olympian_migrations" {
+			continue
+		}
+		lines = append(lines, "table "+table)
+
+		columns, err := dialect.GetColumns(db, table)
+		if err != nil {
+			return nil, fmt.Errorf("failed to snapshot columns of %s: %w", table, err)
+		}
+		for _, col := range columns {
+			dflt := "none"
+			if col.Default != nil {
+				dflt = *col.Default
+			}
+			lines = append(lines, fmt.Sprintf("column %s.%s %s nullable=%t default=%s primary=%t comment=%q",
+				table, col.Name, col.Type, col.Nullable, dflt, col.Primary, col.Comment))
+		}
+
+		constraints, err := sqliteConstraints(db, table)
+		if err != nil {
+			return nil, fmt.Errorf("failed to snapshot constraints of %s: %w", table, err)
+		}
+		lines = append(lines, constraints...)
+
+		indexes, err := dialect.GetIndexes(db, table)
+		if err != nil {
+			return nil, fmt.Errorf("failed to snapshot indexes of %s: %w", table, err)
+		}
+		for _, index := range indexes {
+			lines = append(lines, fmt.Sprintf("index %s.%s (%s) unique=%t",
+				table, index.Name, strings.Join(index.Columns, ", "), index.Unique))
+		}
+
+		foreignKeys, err := dialect.GetForeignKeys(db, table)
+		if err != nil {
+			return nil, fmt.Errorf("failed to snapshot foreign keys of %s: %w", table, err)
+		}
+		for _, fk := range foreignKeys {
+			lines = append(lines, fmt.Sprintf("foreign %s(%s) references %s(%s) on delete %s on update %s",
+				table, strings.Join(fk.Columns, ", "), fk.RefTable, strings.Join(fk.RefColumns, ", "),
+				fk.OnDelete, fk.OnUpdate))
+		}
+	}
+
+	views, err := dialect.GetViews(db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to snapshot views: %w", err)
+	}
+	for _, view := range views {
+		lines = append(lines, fmt.Sprintf("view %s materialized=%t %s",
+			view.Name, view.Materialized, strings.Join(strings.Fields(view.Definition), " ")))
+	}
+
+	sort.Strings(lines)
+	return lines, nil
+}
+
+var (
+	checkClause     = regexp.MustCompile(`(?i)\bCHECK\s*\(`)
+	generatedClause = regexp.MustCompile(`(?i)\bAS\s*\(`)
+)
+
+// sqliteConstraints lists the CHECK constraints and generated column
+// expressions of a table, which SQLite only keeps in its CREATE TABLE
+// statement.
+func sqliteConstraints(db *sql.DB, table string) ([]string, error) {
+	var createSQL string
+	if err := db.QueryRow(`SELECT sql FROM sqlite_master WHERE type = 'table' AND name = ?`, table).Scan(&createSQL); err != nil {
+		return nil, err
+	}
+
+	var lines []string
+	for _, item := range definitionItems(createSQL) {
+		item = strings.Join(strings.Fields(item), " ")
+		first := strings.ToUpper(strings.Fields(item)[0])
+		switch first {
+		case "CONSTRAINT", "CHECK", "PRIMARY", "UNIQUE", "FOREIGN":
+			if checkClause.MatchString(item) {
+				lines = append(lines, fmt.Sprintf("check %s %s", table, item))
+			}
+			continue
+		}
+
+		column := strings.Trim(strings.Fields(item)[0], "\"`[]")
+		if loc := checkClause.FindStringIndex(item); loc != nil {
+			lines = append(lines, fmt.Sprintf("check %s.%s %s", table, column, clause(item, loc)))
+		}
+		if loc := generatedClause.FindStringIndex(item); loc != nil {
+			expression := clause(item, loc)
+			rest := strings.Fields(strings.ToUpper(item[loc[0]+len(expression):]))
+			if len(rest) > 0 && (rest[0] == "STORED" || rest[0] == "VIRTUAL") {
+				expression += " " + rest[0]
+			}
+			lines = append(lines, fmt.Sprintf("generated %s.%s %s", table, column, expression))
+		}
+	}
+	return lines, nil
+}
+
+// definitionItems splits the body of a CREATE TABLE statement into its
+// column definitions and table constraints.
+func definitionItems(createSQL string) []string {
+	var items []string
+	start, depth := -1, 0
+	var quote byte
+	for i := 0; i < len(createSQL); i++ {
+		c := createSQL[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case c == '[':
+			quote = ']'
+		case c == '(':
+			depth++
+			if depth == 1 {
+				start = i + 1
+			}
+		case c == ')':
+			depth--
+			if depth == 0 {
+				return append(items, strings.TrimSpace(createSQL[start:i]))
+			}
+		case c == ',' && depth == 1:
+			items = append(items, strings.TrimSpace(createSQL[start:i]))
+			start = i + 1
+		}
+	}
+	return items
+}
+
+// clause returns the keyword at loc followed by its parenthesized
+// expression.
+func clause(item string, loc []int) string {
+	depth := 0
+	var quote byte
+	for i := loc[1] - 1; i < len(item); i++ {
+		c := item[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+			if depth == 0 {
+				return item[loc[0] : i+1]
+			}
+		}
+	}
+	return item[loc[0]:]
+}
+
+func compare(before, after []string) (missing, extra []string) {
+	seen := make(map[string]bool, len(after))
+	for _, line := range after {
+		seen[line] = true
+	}
+	for _, line := range before {
+		if !seen[line] {
+			missing = append(missing, line)
+		}
+		delete(seen, line)
+	}
+	for _, line := range after {
+		if seen[line] {
+			extra = append(extra, line)
+		}
+	}
+	return missing, extra
+}
@@ -0,0 +1,139 @@
+package olympiantest
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ichtrojan/olympian"
+)
+
+func TestCheckReversible(t *testing.T) {
+	migrations := []olympian.Migration{
+		{
+			Name: "1_create_users_table",
+			Up: func() error {
+				return olympian.Table("users").Create(func() {
+					olympian.Uuid("id").Primary()
+					olympian.String("name")
+				})
+			},
+			Down: func() error {
+				return olympian.Table("users").Drop()
+			},
+		},
+		{
+			Name: "2_add_age_to_users_table",
+			Up: func() error {
+				return olympian.Table("users").Modify(func() {
+					olympian.Integer("age").Nullable()
+				})
+			},
+			Down: func() error {
+				return olympian.Table("users").DropColumn("age")
+			},
+		},
+	}
+
+	AssertReversible(t, migrations, olympian.SQLite())
+}
+
+func TestCheckReversibleReportsFirstFailure(t *testing.T) {
+	migrations := []olympian.Migration{
+		{
+			Name: "1_create_users_table",
+			Up: func() error {
+				return olympian.Table("users").Create(func() {
+					olympian.Uuid("id").Primary()
+				})
+			},
+			Down: func() error {
+				return olympian.Table("users").Drop()
+			},
+		},
+		{
+			Name: "2_add_email_to_users_table",
+			Up: func() error {
+				return olympian.Table("users").Modify(func() {
+					olympian.String("email").Nullable()
+				})
+			},
+			Down: func() error { return nil },
+		},
+		{
+			Name: "3_create_posts_table",
+			Up: func() error {
+				return olympian.Table("posts").Create(func() {
+					olympian.Uuid("id").Primary()
+				})
+			},
+			Down: func() error { return nil },
+		},
+	}
+
+	err := CheckReversible(migrations, nil)
+
+	var notReversible *NotReversibleError
+	if !errors.As(err, &notReversible) {
+		t.Fatalf("Expected NotReversibleError, got %v", err)
+	}
+
+	if notReversible.Migration != "2_add_email_to_users_table" {
+		t.Errorf("Expected 2_add_email_to_users_table to be reported, got %s", notReversible.Migration)
+	}
+
+	if len(notReversible.Extra) == 0 {
+		t.Error("Expected the leftover email column to be reported")
+	}
+}
+
+func TestCheckReversibleComparesChecksAndGeneratedColumns(t *testing.T) {
+	migrations := []olympian.Migration{
+		{
+			Name: "1_create_orders_table",
+			Up: func() error {
+				return olympian.Table("orders").Create(func() {
+					olympian.Integer("id").Primary()
+					olympian.Integer("quantity").Check("quantity > 0")
+					olympian.Integer("price")
+					olympian.Integer("total").VirtualAs("quantity * price")
+				})
+			},
+			Down: func() error {
+				return olympian.Table("orders").Drop()
+			},
+		},
+		{
+			Name: "2_add_price_check_to_orders_table",
+			Up: func() error {
+				return olympian.Table("orders").Modify(func() {
+					olympian.Check("orders_price_positive", "price > 0")
+				})
+			},
+			Down: func() error { return nil },
+		},
+	}
+
+	err := CheckReversible(migrations, nil)
+
+	var notReversible *NotReversibleError
+	if !errors.As(err, &notReversible) {
+		t.Fatalf("Expected NotReversibleError, got %v", err)
+	}
+	if notReversible.Migration != "2_add_price_check_to_orders_table" {
+		t.Errorf("Expected 2_add_price_check_to_orders_table to be reported, got %s", notReversible.Migration)
+	}
+	if len(notReversible.Extra) != 1 || !strings.Contains(notReversible.Extra[0], "orders_price_positive") {
+		t.Errorf("Expected the leftover check to be reported, got %v", notReversible.Extra)
+	}
+
+	if err := CheckReversible(migrations[:1], nil); err != nil {
+		t.Errorf("Expected the first migration to be reversible: %v", err)
+	}
+}
+
+func TestCheckReversibleRejectsOtherDialects(t *testing.T) {
+	if err := CheckReversible(nil, olympian.Postgres()); err == nil {
+		t.Error("Expected an error for a non-SQLite dialect")
+	}
+}
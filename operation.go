@@ -0,0 +1,90 @@
+package olympian
+
+const (
+	OpCreateTable  = "create_table"
+	OpModifyTable  = "modify_table"
+	OpDropTable    = "drop_table"
+	OpDropColumn   = "drop_column"
+	OpRenameColumn = "rename_column"
+	OpRenameTable  = "rename_table"
+	OpCreateIndex  = "create_index"
+	OpDropIndex    = "drop_index"
+	OpChangeColumn = "change_column"
+	OpDropForeign  = "drop_foreign"
+	OpCreateSchema = "create_schema"
+	OpDropSchema   = "drop_schema"
+	OpCreateView   = "create_view"
+	OpDropView     = "drop_view"
+	OpRefreshView  = "refresh_view"
+	OpRawSQL       = "raw_sql"
+)
+
+type Operation struct {
+	Kind  string
+	Table string
+	SQL   []string
+
+	columns []*Column
+	column  string
+	indexes []string
+
+	// exec replaces running SQL statement by statement, for operations that
+	// need a dedicated connection or transaction.
+	exec func(q Querier) error
+}
+
+type MigrationPlan struct {
+	Migration  string
+	Operations []Operation
+}
+
+// recorder collects operations instead of executing them while a migration
+// runs in pretend mode. It is set under mu and read through current().
+var recorder *[]Operation
+
+// scope is the connection and pretend state an operation runs with. It is
+// captured once when the operation starts, so a migration starting or ending
+// on another goroutine cannot redirect it halfway through.
+type scope struct {
+	db       Querier
+	dialect  Dialect
+	tx       bool
+	recorder *[]Operation
+	plan     *pretendPlan
+}
+
+// current captures the transaction of the running migration, if there is
+// one, or the database set with SetDB, along with the pretend state.
+func current() scope {
+	mu.RLock()
+	defer mu.RUnlock()
+	s := scope{db: globalDB, dialect: globalDialect, recorder: recorder, plan: plan}
+	if globalTx != nil {
+		s.db = globalTx
+		s.tx = true
+	}
+	return s
+}
+
+func (s scope) execute(op Operation) error {
+	if s.recorder != nil {
+		*s.recorder = append(*s.recorder, op)
+		if s.plan != nil {
+			s.plan.note(op)
+		}
+		return nil
+	}
+	if op.exec != nil {
+		return op.exec(s.db)
+	}
+	for _, query := range op.SQL {
+		if _, err := s.db.Exec(query); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s scope) pretending() bool {
+	return s.recorder != nil
+}
@@ -0,0 +1,62 @@
+package olympian
+
+import (
+	"fmt"
+	"strings"
+)
+
+// pretendPlan tracks the tables and indexes created by the migrations of a
+// pretend run so far. The live database does not have them yet, so later
+// operations on them skip the lookups that would fail.
+type pretendPlan struct {
+	tables  map[string]bool
+	indexes map[string]string
+}
+
+// plan is set while Pretend runs. Like recorder, it is set under mu and read
+// through current().
+var plan *pretendPlan
+
+func newPretendPlan() *pretendPlan {
+	return &pretendPlan{tables: make(map[string]bool), indexes: make(map[string]string)}
+}
+
+// note records the objects op creates.
+func (p *pretendPlan) note(op Operation) {
+	if op.Kind == OpCreateTable {
+		p.tables[strings.ToLower(op.Table)] = true
+	}
+	for _, index := range op.indexes {
+		p.indexes[strings.ToLower(index)] = op.Table
+	}
+}
+
+// plannedTable reports whether an earlier pending migration creates the
+// table in the current pretend run.
+func (s scope) plannedTable(tableName string) bool {
+	return s.plan != nil && s.plan.tables[strings.ToLower(tableName)]
+}
+
+// plannedIndexTable returns the table of an index created earlier in the
+// current pretend run.
+func (s scope) plannedIndexTable(indexName string) (string, bool) {
+	if s.plan == nil {
+		return "", false
+	}
+	table, ok := s.plan.indexes[strings.ToLower(indexName)]
+	return table, ok
+}
+
+// plannedRebuild stands in for the statements of a SQLite rebuild of a table
+// that does not exist yet, whose definition cannot be read.
+func plannedRebuild(tableName string) []string {
+	return []string{fmt.Sprintf("-- rebuild table %s, created by an earlier pending migration", tableName)}
+}
+
+func indexNames(indexes []*tableIndex) []string {
+	names := make([]string, len(indexes))
+	for i, index := range indexes {
+		names[i] = index.name
+	}
+	return names
+}
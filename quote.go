@@ -0,0 +1,133 @@
+package olympian
+
+import "strings"
+
+// Reserved words that cannot be used as bare identifiers. PostgreSQL's list
+// holds the keywords its grammar reserves outright or for types and
+// functions, MySQL's the (R) keywords of MySQL 8.0 and SQLite's all of its
+// keywords.
+var postgresReservedWords = wordSet(`
+	all analyse analyze and any array as asc asymmetric authorization binary
+	both case cast check collate collation column concurrently constraint
+	create cross current_catalog current_date current_role current_schema
+	current_time current_timestamp current_user default deferrable desc
+	distinct do else end except false fetch for foreign freeze from full grant
+	group having ilike in initially inner intersect into is isnull join
+	lateral leading left like limit localtime localtimestamp natural not
+	notnull null offset on only or order outer overlaps placing primary
+	references returning right select session_user similar some symmetric
+	system_user table tablesample then to trailing true union unique user
+	using variadic verbose when where window with`)
+
+var mysqlReservedWords = wordSet(`
+	accessible add all alter analyze and as asc asensitive before between
+	bigint binary blob both by call cascade case change char character check
+	collate column condition constraint continue convert create cross cube
+	cume_dist current_date current_time current_timestamp current_user cursor
+	database databases day_hour day_microsecond day_minute day_second dec
+	decimal declare default delayed delete dense_rank desc describe
+	deterministic distinct distinctrow div double drop dual each else elseif
+	empty enclosed escaped except exists exit explain false fetch first_value
+	float float4 float8 for force foreign from fulltext function generated
+	get grant group grouping groups having high_priority hour_microsecond
+	hour_minute hour_second if ignore in index infile inner inout insensitive
+	insert int int1 int2 int3 int4 int8 integer intersect interval into
+	io_after_gtids io_before_gtids is iterate join json_table key keys kill
+	lag last_value lateral lead leading leave left like limit linear lines
+	load localtime localtimestamp lock long longblob longtext loop
+	low_priority master_bind master_ssl_verify_server_cert match maxvalue
+	mediumblob mediumint mediumtext middleint minute_microsecond minute_second
+	mod modifies natural not no_write_to_binlog nth_value ntile null numeric
+	of on optimize optimizer_costs option optionally or order out outer
+	outfile over partition percent_rank precision primary procedure purge
+	range rank read read_write reads real recursive references regexp release
+	rename repeat replace require resignal restrict return revoke right rlike
+	row row_number rows schema schemas second_microsecond select sensitive
+	separator set show signal smallint spatial specific sql sql_big_result
+	sql_calc_found_rows sql_small_result sqlexception sqlstate sqlwarning ssl
+	starting stored straight_join system table terminated then tinyblob
+	tinyint tinytext to trailing trigger true undo union unique unlock
+	unsigned update usage use using utc_date utc_time utc_timestamp values
+	varbinary varchar varcharacter varying virtual when where while window
+	with write xor year_month zerofill`)
+
+var sqliteReservedWords = wordSet(`
+	abort action add after all alter always analyze and as asc attach
+	autoincrement before begin between by cascade case cast check collate
+	column commit conflict constraint create cross current current_date
+	current_time current_timestamp database default deferrable deferred
+	delete desc detach distinct do drop each else end escape except exclude
+	exclusive exists explain fail filter first following for foreign from
+	full generated glob group groups having if ignore immediate in index
+	indexed initially inner insert instead intersect into is isnull join key
+	last left like limit match materialized natural no not nothing notnull
+	null nulls of offset on or order others outer over partition plan pragma
+	preceding primary query raise range recursive references regexp reindex
+	release rename replace restrict returning right rollback row rows
+	savepoint select set table temp temporary then ties to transaction
+	trigger unbounded union unique update using vacuum values view virtual
+	when where window with without`)
+
+func wordSet(words string) map[string]bool {
+	set := make(map[string]bool)
+	for _, word := range strings.Fields(words) {
+		set[word] = true
+	}
+	return set
+}
+
+// QuoteIdentifier quotes name with double quotes when it is a reserved word
+// or not a plain identifier, or always with AlwaysQuote. Quoted names are
+// case-sensitive on PostgreSQL.
+func (d *PostgresDialect) QuoteIdentifier(name string) string {
+	return quoteIdentifier(name, '"', '"', postgresReservedWords, d.AlwaysQuote)
+}
+
+// QuoteIdentifier quotes name with backticks when it is a reserved word or
+// not a plain identifier, or always with AlwaysQuote.
+func (d *MySQLDialect) QuoteIdentifier(name string) string {
+	return quoteIdentifier(name, '`', '`', mysqlReservedWords, d.AlwaysQuote)
+}
+
+// QuoteIdentifier quotes name with double quotes when it is a keyword or not
+// a plain identifier, or always with AlwaysQuote.
+func (d *SQLiteDialect) QuoteIdentifier(name string) string {
+	return quoteIdentifier(name, '"', '"', sqliteReservedWords, d.AlwaysQuote)
+}
+
+// quoteIdentifier quotes each part of a qualified name such as public.users
+// separately. Parts that are quoted already are left alone.
+func quoteIdentifier(name string, open, close byte, reserved map[string]bool, always bool) string {
+	parts := strings.Split(name, ".")
+	for i, part := range parts {
+		if len(part) >= 2 && part[0] == open && part[len(part)-1] == close {
+			continue
+		}
+		if always || reserved[strings.ToLower(part)] || !plainIdentifier(part) {
+			escaped := strings.ReplaceAll(part, string(close), string(close)+string(close))
+			parts[i] = string(open) + escaped + string(close)
+		}
+	}
+	return strings.Join(parts, ".")
+}
+
+func plainIdentifier(name string) bool {
+	if name == "" || name[0] >= '0' && name[0] <= '9' {
+		return false
+	}
+	for i := 0; i < len(name); i++ {
+		if !isIdentifierByte(name[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// quoteColumns quotes and joins a column list.
+func quoteColumns(d Dialect, columns []string) string {
+	quoted := make([]string, len(columns))
+	for i, column := range columns {
+		quoted[i] = d.QuoteIdentifier(column)
+	}
+	return strings.Join(quoted, ", ")
+}
@@ -0,0 +1,127 @@
+package olympian
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestQuoteIdentifier(t *testing.T) {
+	tests := []struct {
+		dialect  Dialect
+		name     string
+		expected string
+	}{
+		{&PostgresDialect{}, "users", "users"},
+		{&PostgresDialect{}, "user", `"user"`},
+		{&PostgresDialect{}, "Order", `"Order"`},
+		{&PostgresDialect{}, "public.order", `public."order"`},
+		{&PostgresDialect{}, "first name", `"first name"`},
+		{&PostgresDialect{}, `say"hi`, `"say""hi"`},
+		{&PostgresDialect{}, "2fa", `"2fa"`},
+		{&PostgresDialect{}, `"Users"`, `"Users"`},
+		{&PostgresDialect{AlwaysQuote: true}, "public.users", `"public"."users"`},
+		{&MySQLDialect{}, "key", "`key`"},
+		{&MySQLDialect{}, "rank", "`rank`"},
+		{&MySQLDialect{}, "user", "user"},
+		{&MySQLDialect{}, "odd`name", "`odd``name`"},
+		{&MySQLDialect{AlwaysQuote: true}, "users", "`users`"},
+		{&SQLiteDialect{}, "group", `"group"`},
+		{&SQLiteDialect{}, "users", "users"},
+		{&SQLiteDialect{AlwaysQuote: true}, "users", `"users"`},
+	}
+
+	for _, tt := range tests {
+		if result := tt.dialect.QuoteIdentifier(tt.name); result != tt.expected {
+			t.Errorf("%T.QuoteIdentifier(%s): expected %s, got %s", tt.dialect, tt.name, tt.expected, result)
+		}
+	}
+}
+
+func TestReservedIdentifiersSQL(t *testing.T) {
+	tb := &TableBuilder{
+		tableName: "order",
+		columns: []*Column{
+			{name: "id", dataType: "integer", primary: true},
+			{name: "user", dataType: "integer", index: true},
+			{name: "group", dataType: "string"},
+		},
+	}
+	tb.foreignKeys = []*ForeignKey{{columns: []string{"user"}, refTable: "user", refColumns: []string{"id"}}}
+
+	expected := `CREATE TABLE IF NOT EXISTS "order" (
+  id INTEGER PRIMARY KEY NOT NULL,
+  "user" INTEGER NOT NULL,
+  "group" VARCHAR(255) NOT NULL,
+  CONSTRAINT fk_order_user FOREIGN KEY ("user") REFERENCES "user"(id)
+);;
+CREATE INDEX order_user_index ON "order" ("user")`
+	if sql := buildCreateTable(t, &PostgresDialect{}, tb); sql != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, sql)
+	}
+
+	mysql := buildCreateTable(t, &MySQLDialect{}, tb)
+	for _, fragment := range []string{"CREATE TABLE IF NOT EXISTS `order`", "`group` VARCHAR(255)", "REFERENCES user(id)"} {
+		if !strings.Contains(mysql, fragment) {
+			t.Errorf("MySQL SQL should contain %s, got:\n%s", fragment, mysql)
+		}
+	}
+
+	for _, tt := range []struct {
+		dialect  Dialect
+		expected string
+	}{
+		{&PostgresDialect{}, `ALTER TABLE "order" RENAME COLUMN "group" TO "user"`},
+		{&MySQLDialect{}, "ALTER TABLE `order` RENAME COLUMN `group` TO user"},
+	} {
+		SetDB(nil, tt.dialect)
+		var ops []Operation
+		recorder = &ops
+		err := RenameColumn("order", "group", "user")
+		recorder = nil
+		if err != nil {
+			t.Fatalf("Failed to rename column: %v", err)
+		}
+		if len(ops) != 1 || ops[0].SQL[0] != tt.expected {
+			t.Errorf("%T: expected %s, got %v", tt.dialect, tt.expected, ops)
+		}
+	}
+}
+
+func TestReservedIdentifiersSQLite(t *testing.T) {
+	db := setupTestDB(t)
+	defer func() { _ = db.Close() }()
+
+	SetDB(db, &SQLiteDialect{})
+
+	if err := Table("order").Create(func() {
+		Integer("id").Primary()
+		String("group").Index()
+		String("select").Nullable()
+		Check("limit", `"group" <> ''`)
+	}); err != nil {
+		t.Fatalf("Failed to create table: %v", err)
+	}
+	if err := Table("order").Modify(func() {
+		String("group").Nullable().Change()
+	}); err != nil {
+		t.Fatalf("Failed to change column: %v", err)
+	}
+	if err := Table("order").DropColumn("select"); err != nil {
+		t.Fatalf("Failed to drop column: %v", err)
+	}
+	if err := RenameColumn("order", "group", "where"); err != nil {
+		t.Fatalf("Failed to rename column: %v", err)
+	}
+	if err := RenameTable("order", "table"); err != nil {
+		t.Fatalf("Failed to rename table: %v", err)
+	}
+	if ok, _ := HasColumn("table", "where"); !ok {
+		t.Error("Expected table.where to exist")
+	}
+	if err := DropIndex("order_group_index"); err != nil {
+		t.Fatalf("Failed to drop index: %v", err)
+	}
+	if err := Table("table").Drop(); err != nil {
+		t.Fatalf("Failed to drop table: %v", err)
+	}
+}
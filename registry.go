@@ -32,65 +32,49 @@ func SQLite() Dialect {
 }
 
 func DropColumnIfExists(tableName, columnName string) error {
-	db, dialect := GetDB()
-	query := dialect.BuildDropColumn(tableName, columnName)
-	_, err := db.Exec(query)
-	return err
+	return Table(tableName).DropColumn(columnName)
 }
 
 func RenameColumn(tableName, oldName, newName string) error {
-	db, dialect := GetDB()
-
-	var query string
-	switch dialect.(type) {
-	case *PostgresDialect:
-		query = fmt.Sprintf("ALTER TABLE %s RENAME COLUMN %s TO %s", tableName, oldName, newName)
-	case *MySQLDialect:
-		query = fmt.Sprintf("ALTER TABLE %s RENAME COLUMN %s TO %s", tableName, oldName, newName)
-	case *SQLiteDialect:
-		query = fmt.Sprintf("ALTER TABLE %s RENAME COLUMN %s TO %s", tableName, oldName, newName)
-	}
+	s := current()
+
+	query := fmt.Sprintf("ALTER TABLE %s RENAME COLUMN %s TO %s",
+		s.dialect.QuoteIdentifier(tableName), s.dialect.QuoteIdentifier(oldName), s.dialect.QuoteIdentifier(newName))
 
-	_, err := db.Exec(query)
-	return err
+	return s.execute(Operation{Kind: OpRenameColumn, Table: tableName, SQL: []string{query}, column: oldName})
 }
 
 func RenameTable(oldName, newName string) error {
-	db, _ := GetDB()
-	query := fmt.Sprintf("ALTER TABLE %s RENAME TO %s", oldName, newName)
-	_, err := db.Exec(query)
-	return err
+	s := current()
+	query := fmt.Sprintf("ALTER TABLE %s RENAME TO %s", s.dialect.QuoteIdentifier(oldName), s.dialect.QuoteIdentifier(newName))
+	return s.execute(Operation{Kind: OpRenameTable, Table: oldName, SQL: []string{query}})
 }
 
-func CreateIndex(tableName string, columns []string, indexName string) error {
-	db, _ := GetDB()
-	query := fmt.Sprintf("CREATE INDEX %s ON %s (%s)",
-		indexName, tableName, joinColumns(columns))
-	_, err := db.Exec(query)
-	return err
+func CreateIndex(tableName string, columns []string, indexName string, opts ...IndexOption) error {
+	return createIndex(tableName, columns, indexName, false, opts)
 }
 
-func CreateUniqueIndex(tableName string, columns []string, indexName string) error {
-	db, _ := GetDB()
-	query := fmt.Sprintf("CREATE UNIQUE INDEX %s ON %s (%s)",
-		indexName, tableName, joinColumns(columns))
-	_, err := db.Exec(query)
-	return err
+func CreateUniqueIndex(tableName string, columns []string, indexName string, opts ...IndexOption) error {
+	return createIndex(tableName, columns, indexName, true, opts)
 }
 
+// DropIndex drops an index by name. MySQL needs the table, which is looked
+// up from information_schema.
 func DropIndex(indexName string) error {
-	db, dialect := GetDB()
-
-	var query string
-	switch dialect.(type) {
-	case *MySQLDialect:
-		query = fmt.Sprintf("DROP INDEX %s", indexName)
-	default:
-		query = fmt.Sprintf("DROP INDEX IF EXISTS %s", indexName)
+	s := current()
+
+	var tableName string
+	if table, ok := s.plannedIndexTable(indexName); ok {
+		tableName = table
+	} else if _, isMySQL := s.dialect.(*MySQLDialect); isMySQL {
+		table, err := mysqlIndexTable(s.db, indexName)
+		if err != nil {
+			return err
+		}
+		tableName = table
 	}
 
-	_, err := db.Exec(query)
-	return err
+	return s.execute(Operation{Kind: OpDropIndex, Table: tableName, SQL: []string{dropIndexSQL(s.dialect, tableName, indexName)}})
 }
 
 func joinColumns(columns []string) string {
@@ -0,0 +1,808 @@
+package olympian
+
+import (
+	"database/sql"
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type Schema struct {
+	Tables map[string]*TableInfo
+	Views  map[string]*ViewInfo
+}
+
+type SchemaDiff struct {
+	AddedTables   []*TableInfo
+	RemovedTables []*TableInfo
+	ChangedTables []*TableDiff
+	AddedViews    []*ViewInfo
+	RemovedViews  []*ViewInfo
+	ChangedViews  []ViewChange
+}
+
+type TableDiff struct {
+	Name               string
+	AddedColumns       []ColumnInfo
+	RemovedColumns     []ColumnInfo
+	ChangedColumns     []ColumnChange
+	AddedIndexes       []IndexInfo
+	RemovedIndexes     []IndexInfo
+	AddedForeignKeys   []ForeignKeyInfo
+	RemovedForeignKeys []ForeignKeyInfo
+}
+
+type ColumnChange struct {
+	From ColumnInfo
+	To   ColumnInfo
+}
+
+type ViewChange struct {
+	From *ViewInfo
+	To   *ViewInfo
+}
+
+func InspectSchema(q Querier, dialect Dialect) (*Schema, error) {
+	tables, err := dialect.GetTables(q)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tables: %w", err)
+	}
+
+	schema := &Schema{Tables: make(map[string]*TableInfo)}
+	for _, table := range tables {
+		if table == "olympian_migrations" {
+			continue
+		}
+		info, err := describeTable(q, dialect, table)
+		if err != nil {
+			return nil, err
+		}
+		schema.Tables[table] = info
+	}
+
+	views, err := dialect.GetViews(q)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get views: %w", err)
+	}
+	schema.Views = make(map[string]*ViewInfo, len(views))
+	for i := range views {
+		schema.Views[views[i].Name] = &views[i]
+	}
+	return schema, nil
+}
+
+// OpenScratch opens the database that migrations are replayed into. SQLite
+// falls back to a temporary file; other drivers need an explicit DSN.
+func OpenScratch(driver, dsn string) (*sql.DB, func(), error) {
+	cleanup := func() {}
+
+	if dsn == "" {
+		if driver != "sqlite3" {
+			return nil, nil, fmt.Errorf("a scratch database DSN is required for %s (set DB_SCRATCH_DSN)", driver)
+		}
+		dir, err := os.MkdirTemp("", "olympian-scratch")
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to create scratch directory: %w", err)
+		}
+		dsn = filepath.Join(dir, "scratch.db")
+		cleanup = func() { _ = os.RemoveAll(dir) }
+	}
+
+	db, err := sql.Open(driver, dsn)
+	if err != nil {
+		cleanup()
+		return nil, nil, fmt.Errorf("failed to open scratch database: %w", err)
+	}
+
+	return db, func() {
+		_ = db.Close()
+		cleanup()
+	}, nil
+}
+
+// DiffSchema replays migrations into scratch (dropping whatever it already
+// contains) and compares the result with the live database. Additions in the
+// diff are objects that exist in the live database but not in the migrations.
+// It refuses to run when scratch is the live database.
+func DiffSchema(live, scratch *sql.DB, dialect Dialect, migrations []Migration) (*SchemaDiff, error) {
+	if err := checkScratch(live, scratch, dialect); err != nil {
+		return nil, err
+	}
+
+	prevDB, prevDialect := GetDB()
+	defer SetDB(prevDB, prevDialect)
+
+	migrator := NewMigrator(scratch, dialect)
+	if err := migrator.Init(); err != nil {
+		return nil, fmt.Errorf("failed to initialize scratch database: %w", err)
+	}
+	if err := migrator.Fresh(migrations); err != nil {
+		return nil, fmt.Errorf("failed to replay migrations: %w", err)
+	}
+
+	expected, err := InspectSchema(scratch, dialect)
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect scratch database: %w", err)
+	}
+
+	actual, err := InspectSchema(live, dialect)
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect live database: %w", err)
+	}
+
+	return DiffSchemas(expected, actual), nil
+}
+
+// checkScratch makes sure the scratch database is not the live one, which
+// DiffSchema would wipe.
+func checkScratch(live, scratch *sql.DB, dialect Dialect) error {
+	if live == scratch {
+		return fmt.Errorf("the scratch database is the live database")
+	}
+
+	liveID, err := databaseIdentity(live, dialect)
+	if err != nil {
+		return fmt.Errorf("failed to identify live database: %w", err)
+	}
+	scratchID, err := databaseIdentity(scratch, dialect)
+	if err != nil {
+		return fmt.Errorf("failed to identify scratch database: %w", err)
+	}
+	if liveID != "" && liveID == scratchID {
+		return fmt.Errorf("the scratch database %s is the live database, point DB_SCRATCH_DSN at an empty database", liveID)
+	}
+	return nil
+}
+
+// databaseIdentity names the database q is connected to. It is empty for
+// in-memory SQLite databases, which are never shared.
+func databaseIdentity(q Querier, dialect Dialect) (string, error) {
+	var id string
+	var err error
+	switch dialect.(type) {
+	case *PostgresDialect:
+		err = q.QueryRow(`SELECT current_database() || '@' || COALESCE(host(inet_server_addr()), 'local')
+			|| ':' || COALESCE(inet_server_port(), 0)`).Scan(&id)
+	case *MySQLDialect:
+		err = q.QueryRow("SELECT CONCAT(DATABASE(), '@', @@hostname, ':', @@port)").Scan(&id)
+	case *SQLiteDialect:
+		err = q.QueryRow("SELECT file FROM pragma_database_list WHERE name = 'main'").Scan(&id)
+	}
+	return id, err
+}
+
+func DiffSchemas(expected, actual *Schema) *SchemaDiff {
+	diff := &SchemaDiff{}
+
+	for _, name := range sortedTableNames(actual) {
+		if _, ok := expected.Tables[name]; !ok {
+			diff.AddedTables = append(diff.AddedTables, actual.Tables[name])
+		}
+	}
+
+	for _, name := range sortedTableNames(expected) {
+		want := expected.Tables[name]
+		got, ok := actual.Tables[name]
+		if !ok {
+			diff.RemovedTables = append(diff.RemovedTables, want)
+			continue
+		}
+		if td := diffTable(want, got); !td.empty() {
+			diff.ChangedTables = append(diff.ChangedTables, td)
+		}
+	}
+
+	for _, name := range sortedViewNames(actual) {
+		if _, ok := expected.Views[name]; !ok {
+			diff.AddedViews = append(diff.AddedViews, actual.Views[name])
+		}
+	}
+	for _, name := range sortedViewNames(expected) {
+		want := expected.Views[name]
+		got, ok := actual.Views[name]
+		if !ok {
+			diff.RemovedViews = append(diff.RemovedViews, want)
+			continue
+		}
+		if want.Materialized != got.Materialized || !sameViewDefinition(want.Definition, got.Definition) {
+			diff.ChangedViews = append(diff.ChangedViews, ViewChange{From: want, To: got})
+		}
+	}
+
+	return diff
+}
+
+func diffTable(want, got *TableInfo) *TableDiff {
+	td := &TableDiff{Name: want.Name}
+
+	for _, col := range got.Columns {
+		if _, ok := want.Column(col.Name); !ok {
+			td.AddedColumns = append(td.AddedColumns, col)
+		}
+	}
+	for _, col := range want.Columns {
+		other, ok := got.Column(col.Name)
+		if !ok {
+			td.RemovedColumns = append(td.RemovedColumns, col)
+			continue
+		}
+		if !sameColumn(col, other) {
+			td.ChangedColumns = append(td.ChangedColumns, ColumnChange{From: col, To: other})
+		}
+	}
+
+	td.AddedIndexes = missingIndexes(got.Indexes, want.Indexes)
+	td.RemovedIndexes = missingIndexes(want.Indexes, got.Indexes)
+	td.AddedForeignKeys = missingForeignKeys(got.ForeignKeys, want.ForeignKeys)
+	td.RemovedForeignKeys = missingForeignKeys(want.ForeignKeys, got.ForeignKeys)
+
+	return td
+}
+
+func sameColumn(a, b ColumnInfo) bool {
+	if !strings.EqualFold(a.Type, b.Type) || a.Nullable != b.Nullable || a.Primary != b.Primary {
+		return false
+	}
+	if (a.Default == nil) != (b.Default == nil) {
+		return false
+	}
+	return a.Default == nil || *a.Default == *b.Default
+}
+
+func missingIndexes(from, in []IndexInfo) []IndexInfo {
+	keys := make(map[string]bool, len(in))
+	for _, index := range in {
+		keys[indexKey(index)] = true
+	}
+
+	var missing []IndexInfo
+	for _, index := range from {
+		if !keys[indexKey(index)] {
+			missing = append(missing, index)
+		}
+	}
+	return missing
+}
+
+func indexKey(index IndexInfo) string {
+	return fmt.Sprintf("%s|%s|%t", strings.ToLower(index.Name), strings.Join(index.Columns, ","), index.Unique)
+}
+
+func missingForeignKeys(from, in []ForeignKeyInfo) []ForeignKeyInfo {
+	keys := make(map[string]bool, len(in))
+	for _, fk := range in {
+		keys[foreignKeyKey(fk)] = true
+	}
+
+	var missing []ForeignKeyInfo
+	for _, fk := range from {
+		if !keys[foreignKeyKey(fk)] {
+			missing = append(missing, fk)
+		}
+	}
+	return missing
+}
+
+func foreignKeyKey(fk ForeignKeyInfo) string {
+	return fmt.Sprintf("%s|%s|%s|%s|%s",
+		strings.Join(fk.Columns, ","), fk.RefTable, strings.Join(fk.RefColumns, ","),
+		strings.ToUpper(fk.OnDelete), strings.ToUpper(fk.OnUpdate))
+}
+
+func sortedTableNames(schema *Schema) []string {
+	names := make([]string, 0, len(schema.Tables))
+	for name := range schema.Tables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func sortedViewNames(schema *Schema) []string {
+	names := make([]string, 0, len(schema.Views))
+	for name := range schema.Views {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (td *TableDiff) empty() bool {
+	return len(td.AddedColumns) == 0 && len(td.RemovedColumns) == 0 && len(td.ChangedColumns) == 0 &&
+		len(td.AddedIndexes) == 0 && len(td.RemovedIndexes) == 0 &&
+		len(td.AddedForeignKeys) == 0 && len(td.RemovedForeignKeys) == 0
+}
+
+func (d *SchemaDiff) Empty() bool {
+	return len(d.AddedTables) == 0 && len(d.RemovedTables) == 0 && len(d.ChangedTables) == 0 &&
+		len(d.AddedViews) == 0 && len(d.RemovedViews) == 0 && len(d.ChangedViews) == 0
+}
+
+func (d *SchemaDiff) String() string {
+	if d.Empty() {
+		return "Schema matches migrations\n"
+	}
+
+	var b strings.Builder
+	for _, table := range d.AddedTables {
+		fmt.Fprintf(&b, "+ table %s\n", table.Name)
+	}
+	for _, table := range d.RemovedTables {
+		fmt.Fprintf(&b, "- table %s\n", table.Name)
+	}
+	for _, td := range d.ChangedTables {
+		fmt.Fprintf(&b, "~ table %s\n", td.Name)
+		for _, col := range td.AddedColumns {
+			fmt.Fprintf(&b, "    + column %s %s\n", col.Name, describeColumn(col))
+		}
+		for _, col := range td.RemovedColumns {
+			fmt.Fprintf(&b, "    - column %s %s\n", col.Name, describeColumn(col))
+		}
+		for _, change := range td.ChangedColumns {
+			fmt.Fprintf(&b, "    ~ column %s %s -> %s\n", change.From.Name, describeColumn(change.From), describeColumn(change.To))
+		}
+		for _, index := range td.AddedIndexes {
+			fmt.Fprintf(&b, "    + index %s (%s)\n", index.Name, strings.Join(index.Columns, ", "))
+		}
+		for _, index := range td.RemovedIndexes {
+			fmt.Fprintf(&b, "    - index %s (%s)\n", index.Name, strings.Join(index.Columns, ", "))
+		}
+		for _, fk := range td.AddedForeignKeys {
+			fmt.Fprintf(&b, "    + foreign key %s\n", describeForeignKey(fk))
+		}
+		for _, fk := range td.RemovedForeignKeys {
+			fmt.Fprintf(&b, "    - foreign key %s\n", describeForeignKey(fk))
+		}
+	}
+	for _, view := range d.AddedViews {
+		fmt.Fprintf(&b, "+ %s %s\n", viewKind(view), view.Name)
+	}
+	for _, view := range d.RemovedViews {
+		fmt.Fprintf(&b, "- %s %s\n", viewKind(view), view.Name)
+	}
+	for _, change := range d.ChangedViews {
+		fmt.Fprintf(&b, "~ %s %s\n", viewKind(change.To), change.To.Name)
+	}
+	return b.String()
+}
+
+func viewKind(view *ViewInfo) string {
+	if view.Materialized {
+		return "materialized view"
+	}
+	return "view"
+}
+
+func describeColumn(col ColumnInfo) string {
+	desc := col.Type
+	if col.Nullable {
+		desc += " NULL"
+	} else {
+		desc += " NOT NULL"
+	}
+	if col.Default != nil {
+		desc += " DEFAULT " + *col.Default
+	}
+	if col.Primary {
+		desc += " PRIMARY KEY"
+	}
+	return desc
+}
+
+func describeForeignKey(fk ForeignKeyInfo) string {
+	return fmt.Sprintf("(%s) REFERENCES %s(%s)",
+		strings.Join(fk.Columns, ", "), fk.RefTable, strings.Join(fk.RefColumns, ", "))
+}
+
+// WriteMigration writes the diff as a migration that brings the migrations
+// in line with the live database, and returns the path of the new file.
+func (d *SchemaDiff) WriteMigration(dir, name string) (string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create migrations directory: %w", err)
+	}
+
+	migrationName := fmt.Sprintf("%d_%s", GetTimestamp(), name)
+	source, err := d.Migration(migrationName)
+	if err != nil {
+		return "", err
+	}
+
+	path := filepath.Join(dir, migrationName+".go")
+	if err := os.WriteFile(path, source, 0644); err != nil {
+		return "", fmt.Errorf("failed to write migration: %w", err)
+	}
+	return path, nil
+}
+
+func (d *SchemaDiff) Migration(name string) ([]byte, error) {
+	var up, down []string
+
+	// Views read tables, so removed views are dropped before any table
+	// changes and added views are created after them.
+	for _, view := range d.RemovedViews {
+		up = append(up, dropViewCall(view))
+		down = append(down, createViewCall(view))
+	}
+
+	for _, table := range d.AddedTables {
+		up = append(up, createTableCall(table))
+		down = append(down, fmt.Sprintf("olympian.Table(%q).Drop()", table.Name))
+	}
+	for _, table := range d.RemovedTables {
+		up = append(up, fmt.Sprintf("olympian.Table(%q).Drop()", table.Name))
+		down = append(down, createTableCall(table))
+	}
+
+	for _, td := range d.ChangedTables {
+		if len(td.AddedColumns) > 0 {
+			up = append(up, modifyTableCall(td.Name, td.AddedColumns))
+		}
+		for _, col := range td.RemovedColumns {
+			up = append(up, fmt.Sprintf("olympian.Table(%q).DropColumn(%q)", td.Name, col.Name))
+		}
+		if len(td.RemovedColumns) > 0 {
+			down = append(down, modifyTableCall(td.Name, td.RemovedColumns))
+		}
+		for _, col := range td.AddedColumns {
+			down = append(down, fmt.Sprintf("olympian.Table(%q).DropColumn(%q)", td.Name, col.Name))
+		}
+
+		for _, change := range td.ChangedColumns {
+			up = append(up, changeColumnCall(td.Name, change.To))
+			up = append(up, columnChangeTodos(td.Name, change.From, change.To)...)
+			down = append(down, changeColumnCall(td.Name, change.From))
+			down = append(down, columnChangeTodos(td.Name, change.To, change.From)...)
+		}
+
+		for _, index := range td.AddedIndexes {
+			if !generatedIndex(index) {
+				up = append(up, createIndexCall(td.Name, index))
+				down = append(down, fmt.Sprintf("olympian.Table(%q).DropIndex(%q)", td.Name, index.Name))
+			}
+		}
+		for _, index := range td.RemovedIndexes {
+			if !generatedIndex(index) {
+				up = append(up, fmt.Sprintf("olympian.Table(%q).DropIndex(%q)", td.Name, index.Name))
+				down = append(down, createIndexCall(td.Name, index))
+			}
+		}
+
+		for _, fk := range td.AddedForeignKeys {
+			up = append(up, addForeignCall(td.Name, fk))
+			down = append(down, dropForeignCall(td.Name, fk))
+		}
+		for _, fk := range td.RemovedForeignKeys {
+			up = append(up, dropForeignCall(td.Name, fk))
+			down = append(down, addForeignCall(td.Name, fk))
+		}
+	}
+
+	for _, change := range d.ChangedViews {
+		if !change.From.Materialized && !change.To.Materialized {
+			up = append(up, replaceViewCall(change.To))
+			down = append(down, replaceViewCall(change.From))
+			continue
+		}
+		up = append(up, dropViewCall(change.From), createViewCall(change.To))
+		down = append(down, createViewCall(change.From), dropViewCall(change.To))
+	}
+	for _, view := range d.AddedViews {
+		up = append(up, createViewCall(view))
+		down = append(down, dropViewCall(view))
+	}
+
+	// Down undoes Up, so its operations run in reverse order.
+	for i, j := 0, len(down)-1; i < j; i, j = i+1, j-1 {
+		down[i], down[j] = down[j], down[i]
+	}
+
+	source := fmt.Sprintf(`package migrations
+
+import (
+	"github.com/ichtrojan/olympian"
+)
+
+func init() {
+	olympian.RegisterMigration(olympian.Migration{
+		Name: %q,
+		Up: func() error {
+%s
+		},
+		Down: func() error {
+%s
+		},
+	})
+}
+`, name, migrationBody(up), migrationBody(down))
+
+	formatted, err := format.Source([]byte(source))
+	if err != nil {
+		return nil, fmt.Errorf("failed to format migration: %w", err)
+	}
+	return formatted, nil
+}
+
+func migrationBody(calls []string) string {
+	var b strings.Builder
+	for _, call := range calls {
+		if strings.HasPrefix(call, "//") {
+			b.WriteString(call + "\n")
+			continue
+		}
+		fmt.Fprintf(&b, "if err := %s; err != nil {\nreturn err\n}\n", call)
+	}
+	b.WriteString("return nil")
+	return b.String()
+}
+
+func createTableCall(table *TableInfo) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "olympian.Table(%q).Create(func() {\n", table.Name)
+
+	var primary []string
+	for _, col := range table.Columns {
+		if col.Primary {
+			primary = append(primary, strconv.Quote(col.Name))
+		}
+	}
+	for _, col := range table.Columns {
+		if len(primary) > 1 {
+			col.Primary = false
+		}
+		b.WriteString(columnCall(col) + "\n")
+	}
+	if len(primary) > 1 {
+		fmt.Fprintf(&b, "olympian.PrimaryKey(%s)\n", strings.Join(primary, ", "))
+	}
+	for _, fk := range table.ForeignKeys {
+		b.WriteString(foreignCall(table.Name, fk) + "\n")
+	}
+	b.WriteString("})")
+	return b.String()
+}
+
+func foreignCall(tableName string, fk ForeignKeyInfo) string {
+	call := fmt.Sprintf("olympian.Foreign(%s).References(%s).On(%q)",
+		quoteAll(fk.Columns), quoteAll(fk.RefColumns), fk.RefTable)
+	if fk.Name != "" && fk.Name != foreignKeyName(tableName, fk.Columns) {
+		call += fmt.Sprintf(".Name(%q)", fk.Name)
+	}
+	if rule := strings.ToLower(fk.OnDelete); rule != "" && rule != "no action" {
+		call += fmt.Sprintf(".OnDelete(%q)", rule)
+	}
+	if rule := strings.ToLower(fk.OnUpdate); rule != "" && rule != "no action" {
+		call += fmt.Sprintf(".OnUpdate(%q)", rule)
+	}
+	return call
+}
+
+func addForeignCall(tableName string, fk ForeignKeyInfo) string {
+	return fmt.Sprintf("olympian.Table(%q).Modify(func() {\n%s\n})", tableName, foreignCall(tableName, fk))
+}
+
+func dropForeignCall(tableName string, fk ForeignKeyInfo) string {
+	name := fk.Name
+	if name == "" {
+		name = foreignKeyName(tableName, fk.Columns)
+	}
+	return fmt.Sprintf("olympian.Table(%q).DropForeign(%q)", tableName, name)
+}
+
+func quoteAll(values []string) string {
+	quoted := make([]string, len(values))
+	for i, value := range values {
+		quoted[i] = strconv.Quote(value)
+	}
+	return strings.Join(quoted, ", ")
+}
+
+func modifyTableCall(tableName string, columns []ColumnInfo) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "olympian.Table(%q).Modify(func() {\n", tableName)
+	for _, col := range columns {
+		b.WriteString(columnCall(col) + "\n")
+	}
+	b.WriteString("})")
+	return b.String()
+}
+
+func createViewCall(view *ViewInfo) string {
+	if view.Materialized {
+		return fmt.Sprintf("olympian.CreateMaterializedView(%q, %s)", view.Name, viewQuery(view.Definition))
+	}
+	return fmt.Sprintf("olympian.CreateView(%q, %s)", view.Name, viewQuery(view.Definition))
+}
+
+func replaceViewCall(view *ViewInfo) string {
+	return fmt.Sprintf("olympian.CreateOrReplaceView(%q, %s)", view.Name, viewQuery(view.Definition))
+}
+
+func dropViewCall(view *ViewInfo) string {
+	if view.Materialized {
+		return fmt.Sprintf("olympian.DropMaterializedView(%q)", view.Name)
+	}
+	return fmt.Sprintf("olympian.DropView(%q)", view.Name)
+}
+
+// viewQuery renders a view query as a raw string literal so multi-line
+// queries stay readable in the migration.
+func viewQuery(query string) string {
+	query = strings.TrimSuffix(strings.TrimSpace(query), ";")
+	if strings.Contains(query, "`") {
+		return strconv.Quote(query)
+	}
+	return "`" + query + "`"
+}
+
+// changeColumnCall alters a column to match col. Change keeps the current
+// nullability unless told otherwise, so NOT NULL is spelled out.
+func changeColumnCall(tableName string, col ColumnInfo) string {
+	col.Primary = false
+	call := columnCall(col)
+
+	modifiers := ".Change()"
+	if !col.Nullable {
+		modifiers = ".Nullable(false)" + modifiers
+	}
+	if i := strings.Index(call, " //"); i >= 0 {
+		call = call[:i] + modifiers + call[i:]
+	} else {
+		call += modifiers
+	}
+	return fmt.Sprintf("olympian.Table(%q).Modify(func() {\n%s\n})", tableName, call)
+}
+
+// columnChangeTodos notes the parts of a column change that Change cannot
+// express: a dropped default and a changed primary key.
+func columnChangeTodos(tableName string, from, to ColumnInfo) []string {
+	var todos []string
+	if from.Default != nil && to.Default == nil {
+		todos = append(todos, fmt.Sprintf("// TODO: drop the default of column %s.%s", tableName, to.Name))
+	}
+	if from.Primary != to.Primary {
+		todos = append(todos, fmt.Sprintf("// TODO: column %s.%s changed from %s to %s",
+			tableName, to.Name, describeColumn(from), describeColumn(to)))
+	}
+	return todos
+}
+
+func createIndexCall(tableName string, index IndexInfo) string {
+	fn := "CreateIndex"
+	if index.Unique {
+		fn = "CreateUniqueIndex"
+	}
+	return fmt.Sprintf("olympian.%s(%q, %#v, %q)", fn, tableName, index.Columns, index.Name)
+}
+
+// generatedIndex reports indexes the database creates on its own for
+// primary keys and inline UNIQUE constraints.
+func generatedIndex(index IndexInfo) bool {
+	return index.Primary || strings.HasPrefix(index.Name, "sqlite_autoindex_")
+}
+
+var (
+	currentTimestampPattern = regexp.MustCompile(`(?i)^(current_timestamp(\(\))?|now\(\))$`)
+	lengthTypePattern       = regexp.MustCompile(`\((\d+)\)$`)
+	decimalTypePattern      = regexp.MustCompile(`^(?:numeric|decimal)\((\d+),\s*(\d+)\)`)
+	defaultCastPattern      = regexp.MustCompile(`::[a-z ]+(\(\d+\))?$`)
+	numericPattern          = regexp.MustCompile(`^-?\d+(\.\d+)?$`)
+)
+
+func columnCall(col ColumnInfo) string {
+	dataType := strings.ToLower(col.Type)
+
+	var call string
+	switch {
+	case strings.Contains(dataType, "uuid") || dataType == "char(36)":
+		call = fmt.Sprintf("olympian.Uuid(%q)", col.Name)
+	case dataType == "tinyint(1)" || strings.HasPrefix(dataType, "bool"):
+		call = fmt.Sprintf("olympian.Boolean(%q)", col.Name)
+	case strings.HasPrefix(dataType, "bigint") || dataType == "bigserial":
+		call = fmt.Sprintf("olympian.BigInteger(%q)", col.Name)
+	case strings.HasPrefix(dataType, "smallint") || dataType == "smallserial":
+		call = fmt.Sprintf("olympian.SmallInteger(%q)", col.Name)
+	case strings.HasPrefix(dataType, "tinyint"):
+		call = fmt.Sprintf("olympian.TinyInteger(%q)", col.Name)
+	case strings.HasPrefix(dataType, "int") || dataType == "serial":
+		call = fmt.Sprintf("olympian.Integer(%q)", col.Name)
+	case decimalTypePattern.MatchString(dataType):
+		m := decimalTypePattern.FindStringSubmatch(dataType)
+		call = fmt.Sprintf("olympian.Decimal(%q, %s, %s)", col.Name, m[1], m[2])
+	case dataType == "real" || strings.HasPrefix(dataType, "float"):
+		call = fmt.Sprintf("olympian.Float(%q)", col.Name)
+	case strings.HasPrefix(dataType, "double"):
+		call = fmt.Sprintf("olympian.Double(%q)", col.Name)
+	case strings.HasPrefix(dataType, "timestamp"):
+		call = fmt.Sprintf("olympian.Timestamp(%q)", col.Name)
+	case strings.HasPrefix(dataType, "datetime"):
+		call = fmt.Sprintf("olympian.DateTime(%q)", col.Name)
+	case dataType == "date":
+		call = fmt.Sprintf("olympian.Date(%q)", col.Name)
+	case strings.HasPrefix(dataType, "time"):
+		call = fmt.Sprintf("olympian.Time(%q)", col.Name)
+	case strings.HasPrefix(dataType, "year"):
+		call = fmt.Sprintf("olympian.Year(%q)", col.Name)
+	case strings.HasPrefix(dataType, "json"):
+		call = fmt.Sprintf("olympian.Json(%q)", col.Name)
+	case dataType == "text":
+		call = fmt.Sprintf("olympian.Text(%q)", col.Name)
+	case dataType == "mediumtext":
+		call = fmt.Sprintf("olympian.MediumText(%q)", col.Name)
+	case dataType == "longtext":
+		call = fmt.Sprintf("olympian.LongText(%q)", col.Name)
+	case dataType == "bytea" || strings.HasSuffix(dataType, "blob"):
+		call = fmt.Sprintf("olympian.Blob(%q)", col.Name)
+	case strings.HasPrefix(dataType, "varbinary") || strings.HasPrefix(dataType, "binary"):
+		call = fmt.Sprintf("olympian.Binary(%q%s)", col.Name, lengthArg(dataType, 255))
+	case dataType == "inet":
+		call = fmt.Sprintf("olympian.IpAddress(%q)", col.Name)
+	case dataType == "macaddr":
+		call = fmt.Sprintf("olympian.MacAddress(%q)", col.Name)
+	case strings.HasPrefix(dataType, "char(") || strings.HasPrefix(dataType, "character("):
+		call = fmt.Sprintf("olympian.Char(%q%s)", col.Name, lengthArg(dataType, 0))
+	case strings.Contains(dataType, "char"):
+		call = fmt.Sprintf("olympian.String(%q%s)", col.Name, lengthArg(dataType, 255))
+	default:
+		call = fmt.Sprintf("olympian.String(%q) // TODO: verify type %s", col.Name, col.Type)
+	}
+
+	var modifiers string
+	if col.Primary {
+		modifiers += ".Primary()"
+	}
+	if col.Nullable {
+		modifiers += ".Nullable()"
+	}
+	if col.AutoIncrement {
+		modifiers += ".AutoIncrement()"
+	}
+	if col.Default != nil {
+		if strings.HasPrefix(*col.Default, "nextval(") {
+			modifiers += ".AutoIncrement()"
+		} else if literal, ok := defaultLiteral(*col.Default); ok {
+			modifiers += fmt.Sprintf(".Default(%s)", literal)
+		} else if currentTimestampPattern.MatchString(*col.Default) {
+			modifiers += ".UseCurrent()"
+		}
+	}
+	if col.Comment != "" {
+		modifiers += fmt.Sprintf(".Comment(%q)", col.Comment)
+	}
+
+	if i := strings.Index(call, " //"); i >= 0 {
+		return call[:i] + modifiers + call[i:]
+	}
+	return call + modifiers
+}
+
+// lengthArg returns the ", n" argument for a type such as varchar(100), or
+// nothing when the length is the builder's default.
+func lengthArg(dataType string, fallback int) string {
+	m := lengthTypePattern.FindStringSubmatch(dataType)
+	if m == nil || m[1] == strconv.Itoa(fallback) {
+		return ""
+	}
+	return ", " + m[1]
+}
+
+// defaultLiteral turns a default reported by the database into a Go literal.
+// Expressions are not representable; columnCall maps CURRENT_TIMESTAMP to
+// UseCurrent and skips the rest.
+func defaultLiteral(value string) (string, bool) {
+	value = defaultCastPattern.ReplaceAllString(strings.TrimSpace(value), "")
+
+	switch lower := strings.ToLower(value); {
+	case lower == "true" || lower == "false":
+		return lower, true
+	case numericPattern.MatchString(value):
+		return value, true
+	case len(value) >= 2 && value[0] == '\'' && value[len(value)-1] == '\'':
+		return fmt.Sprintf("%q", strings.ReplaceAll(value[1:len(value)-1], "''", "'")), true
+	}
+	return "", false
+}
@@ -0,0 +1,289 @@
+package olympian
+
+import (
+	"database/sql"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func schemaDiffMigrations() []Migration {
+	return []Migration{
+		{
+			Name: "1_create_users_table",
+			Up: func() error {
+				return Table("users").Create(func() {
+					Uuid("id").Primary()
+					String("name")
+					String("legacy_code").Nullable()
+				})
+			},
+			Down: func() error {
+				return Table("users").Drop()
+			},
+		},
+		{
+			Name: "2_create_sessions_table",
+			Up: func() error {
+				return Table("sessions").Create(func() {
+					Uuid("id").Primary()
+				})
+			},
+			Down: func() error {
+				return Table("sessions").Drop()
+			},
+		},
+	}
+}
+
+func TestDiffSchema(t *testing.T) {
+	live, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "live.db"))
+	if err != nil {
+		t.Fatalf("Failed to open live database: %v", err)
+	}
+	defer func() { _ = live.Close() }()
+
+	migrator := NewMigrator(live, &SQLiteDialect{})
+	if err := migrator.Init(); err != nil {
+		t.Fatalf("Failed to initialize migrator: %v", err)
+	}
+	if err := migrator.Migrate(schemaDiffMigrations()); err != nil {
+		t.Fatalf("Failed to run migrations: %v", err)
+	}
+
+	hotfixes := []string{
+		"ALTER TABLE users ADD COLUMN phone TEXT",
+		"ALTER TABLE users DROP COLUMN legacy_code",
+		"CREATE INDEX idx_users_name ON users (name)",
+		"DROP TABLE sessions",
+		"CREATE TABLE audit_log (id INTEGER PRIMARY KEY, note TEXT NOT NULL DEFAULT 'n/a')",
+	}
+	for _, query := range hotfixes {
+		if _, err := live.Exec(query); err != nil {
+			t.Fatalf("Failed to apply hotfix %q: %v", query, err)
+		}
+	}
+
+	scratch, cleanup, err := OpenScratch("sqlite3", "")
+	if err != nil {
+		t.Fatalf("Failed to open scratch database: %v", err)
+	}
+	defer cleanup()
+
+	diff, err := DiffSchema(live, scratch, &SQLiteDialect{}, schemaDiffMigrations())
+	if err != nil {
+		t.Fatalf("Failed to diff schema: %v", err)
+	}
+
+	if len(diff.AddedTables) != 1 || diff.AddedTables[0].Name != "audit_log" {
+		t.Errorf("Expected audit_log to be added, got %v", diff.AddedTables)
+	}
+	if len(diff.RemovedTables) != 1 || diff.RemovedTables[0].Name != "sessions" {
+		t.Errorf("Expected sessions to be removed, got %v", diff.RemovedTables)
+	}
+	if len(diff.ChangedTables) != 1 {
+		t.Fatalf("Expected 1 changed table, got %d", len(diff.ChangedTables))
+	}
+
+	users := diff.ChangedTables[0]
+	if len(users.AddedColumns) != 1 || users.AddedColumns[0].Name != "phone" {
+		t.Errorf("Expected phone to be added, got %v", users.AddedColumns)
+	}
+	if len(users.RemovedColumns) != 1 || users.RemovedColumns[0].Name != "legacy_code" {
+		t.Errorf("Expected legacy_code to be removed, got %v", users.RemovedColumns)
+	}
+	if len(users.AddedIndexes) != 1 || users.AddedIndexes[0].Name != "idx_users_name" {
+		t.Errorf("Expected idx_users_name to be added, got %v", users.AddedIndexes)
+	}
+
+	report := diff.String()
+	for _, expected := range []string{"+ table audit_log", "- table sessions", "+ column phone", "- column legacy_code"} {
+		if !strings.Contains(report, expected) {
+			t.Errorf("Report should contain %q:\n%s", expected, report)
+		}
+	}
+}
+
+func TestDiffSchemaRefusesLiveScratch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "live.db")
+	live, err := sql.Open("sqlite3", path)
+	if err != nil {
+		t.Fatalf("Failed to open live database: %v", err)
+	}
+	defer func() { _ = live.Close() }()
+	if _, err := live.Exec("CREATE TABLE customers (id INTEGER PRIMARY KEY)"); err != nil {
+		t.Fatalf("Failed to create table: %v", err)
+	}
+
+	scratch, err := sql.Open("sqlite3", path)
+	if err != nil {
+		t.Fatalf("Failed to open scratch database: %v", err)
+	}
+	defer func() { _ = scratch.Close() }()
+
+	for _, db := range []*sql.DB{live, scratch} {
+		if _, err := DiffSchema(live, db, &SQLiteDialect{}, schemaDiffMigrations()); err == nil {
+			t.Error("Expected DiffSchema to refuse the live database as scratch")
+		}
+	}
+
+	var count int
+	if err := live.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE name = 'customers'").Scan(&count); err != nil || count != 1 {
+		t.Errorf("Expected the live database to be left alone: %v", err)
+	}
+}
+
+func TestSchemaDiffMigration(t *testing.T) {
+	defaultNote := "'n/a'"
+	defaultStatus := "'active'"
+	diff := &SchemaDiff{
+		AddedTables: []*TableInfo{
+			{
+				Name: "audit_log",
+				Columns: []ColumnInfo{
+					{Name: "id", Type: "INTEGER", Primary: true},
+					{Name: "note", Type: "TEXT", Default: &defaultNote},
+				},
+			},
+		},
+		ChangedTables: []*TableDiff{
+			{
+				Name:         "users",
+				AddedColumns: []ColumnInfo{{Name: "phone", Type: "character varying(255)", Nullable: true}},
+				AddedIndexes: []IndexInfo{{Name: "idx_users_name", Columns: []string{"name"}}},
+				AddedForeignKeys: []ForeignKeyInfo{
+					{Columns: []string{"tenant_id", "team_id"}, RefTable: "teams", RefColumns: []string{"tenant_id", "id"}, OnDelete: "CASCADE"},
+				},
+				RemovedForeignKeys: []ForeignKeyInfo{
+					{Name: "users_manager_fk", Columns: []string{"manager_id"}, RefTable: "users", RefColumns: []string{"id"}},
+				},
+				ChangedColumns: []ColumnChange{
+					{
+						From: ColumnInfo{Name: "name", Type: "character varying(100)", Nullable: true},
+						To:   ColumnInfo{Name: "name", Type: "character varying(255)"},
+					},
+					{
+						From: ColumnInfo{Name: "status", Type: "character varying(255)", Default: &defaultStatus},
+						To:   ColumnInfo{Name: "status", Type: "text"},
+					},
+				},
+			},
+		},
+	}
+
+	source, err := diff.Migration("1700000000_sync_schema")
+	if err != nil {
+		t.Fatalf("Failed to generate migration: %v", err)
+	}
+
+	if _, err := parser.ParseFile(token.NewFileSet(), "migration.go", source, 0); err != nil {
+		t.Fatalf("Generated migration does not parse: %v\n%s", err, source)
+	}
+
+	code := string(source)
+	expected := []string{
+		`Name: "1700000000_sync_schema"`,
+		`olympian.Table("audit_log").Create(func() {`,
+		`olympian.Integer("id").Primary()`,
+		`olympian.Text("note").Default("n/a")`,
+		`olympian.String("phone").Nullable()`,
+		`olympian.CreateIndex("users", []string{"name"}, "idx_users_name")`,
+		`olympian.Table("users").DropIndex("idx_users_name")`,
+		`olympian.Table("users").DropColumn("phone")`,
+		`olympian.Table("audit_log").Drop()`,
+		`olympian.Foreign("tenant_id", "team_id").References("tenant_id", "id").On("teams").OnDelete("cascade")`,
+		`olympian.Table("users").DropForeign("fk_users_tenant_id_team_id")`,
+		`olympian.Table("users").DropForeign("users_manager_fk")`,
+		`olympian.Foreign("manager_id").References("id").On("users").Name("users_manager_fk")`,
+		`olympian.String("name").Nullable(false).Change()`,
+		`olympian.String("name", 100).Nullable().Change()`,
+		`olympian.Text("status").Nullable(false).Change()`,
+		`olympian.String("status").Default("active").Nullable(false).Change()`,
+		`// TODO: drop the default of column users.status`,
+	}
+	for _, e := range expected {
+		if !strings.Contains(code, e) {
+			t.Errorf("Generated migration should contain %s:\n%s", e, code)
+		}
+	}
+}
+
+func TestDefaultLiteral(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected string
+		ok       bool
+	}{
+		{"0", "0", true},
+		{"true", "true", true},
+		{"'active'::character varying", `"active"`, true},
+		{"'O''Reilly'", `"O'Reilly"`, true},
+		{"CURRENT_TIMESTAMP", "", false},
+	}
+
+	for _, tt := range tests {
+		result, ok := defaultLiteral(tt.value)
+		if result != tt.expected || ok != tt.ok {
+			t.Errorf("defaultLiteral(%q) = %q, %v; expected %q, %v", tt.value, result, ok, tt.expected, tt.ok)
+		}
+	}
+}
+
+func TestColumnCall(t *testing.T) {
+	tests := []struct {
+		dataType string
+		expected string
+	}{
+		{"character varying(255)", `olympian.String("c")`},
+		{"varchar(64)", `olympian.String("c", 64)`},
+		{"character(2)", `olympian.Char("c", 2)`},
+		{"smallint", `olympian.SmallInteger("c")`},
+		{"tinyint(4)", `olympian.TinyInteger("c")`},
+		{"double precision", `olympian.Double("c")`},
+		{"real", `olympian.Float("c")`},
+		{"datetime", `olympian.DateTime("c")`},
+		{"time without time zone", `olympian.Time("c")`},
+		{"year", `olympian.Year("c")`},
+		{"longtext", `olympian.LongText("c")`},
+		{"bytea", `olympian.Blob("c")`},
+		{"varbinary(16)", `olympian.Binary("c", 16)`},
+		{"inet", `olympian.IpAddress("c")`},
+		{"macaddr", `olympian.MacAddress("c")`},
+	}
+
+	for _, tt := range tests {
+		if result := columnCall(ColumnInfo{Name: "c", Type: tt.dataType}); result != tt.expected {
+			t.Errorf("columnCall(%s) = %s, expected %s", tt.dataType, result, tt.expected)
+		}
+	}
+}
+
+func TestMySQLColumnDefaults(t *testing.T) {
+	tests := []struct {
+		value      string
+		columnType string
+		extra      string
+		expected   string
+		call       string
+	}{
+		{"active", "varchar(20)", "", "'active'", `olympian.String("c", 20).Default("active")`},
+		{"O'Reilly", "varchar(20)", "", "'O''Reilly'", `olympian.String("c", 20).Default("O'Reilly")`},
+		{"5", "varchar(20)", "", "'5'", `olympian.String("c", 20).Default("5")`},
+		{"0", "int", "", "0", `olympian.Integer("c").Default(0)`},
+		{"CURRENT_TIMESTAMP", "timestamp", "DEFAULT_GENERATED", "CURRENT_TIMESTAMP", `olympian.Timestamp("c").UseCurrent()`},
+		{"uuid()", "char(36)", "DEFAULT_GENERATED", "(uuid())", `olympian.Uuid("c")`},
+	}
+
+	for _, tt := range tests {
+		result := mysqlColumnDefault(tt.value, tt.columnType, tt.extra)
+		if result != tt.expected {
+			t.Errorf("mysqlColumnDefault(%q) = %s, expected %s", tt.value, result, tt.expected)
+		}
+		col := ColumnInfo{Name: "c", Type: tt.columnType, Default: &result}
+		if call := columnCall(col); call != tt.call {
+			t.Errorf("columnCall with default %s = %s, expected %s", result, call, tt.call)
+		}
+	}
+}
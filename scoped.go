@@ -0,0 +1,201 @@
+package olympian
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+)
+
+// scopedDB returns a *sql.DB that sends every statement to q, so raw SQL run
+// through GetDB inside a migration joins the migration's transaction. When
+// recorder is set, statements that change the database are recorded in it
+// instead and queries read from q.
+func scopedDB(q Querier, recorder *[]Operation) *sql.DB {
+	return sql.OpenDB(&scopedConnector{q: q, recorder: recorder})
+}
+
+type scopedConnector struct {
+	q        Querier
+	recorder *[]Operation
+}
+
+func (c *scopedConnector) Connect(context.Context) (driver.Conn, error) {
+	return &scopedConn{c}, nil
+}
+
+func (c *scopedConnector) Driver() driver.Driver {
+	return scopedDriver{}
+}
+
+type scopedDriver struct{}
+
+func (scopedDriver) Open(string) (driver.Conn, error) {
+	return nil, errors.New("olympian: scoped connections cannot be opened by name")
+}
+
+type scopedConn struct {
+	c *scopedConnector
+}
+
+func (c *scopedConn) Prepare(query string) (driver.Stmt, error) {
+	return &scopedStmt{conn: c, query: query}, nil
+}
+
+func (c *scopedConn) Close() error {
+	return nil
+}
+
+func (c *scopedConn) Begin() (driver.Tx, error) {
+	if c.c.recorder != nil {
+		return scopedTx{}, nil
+	}
+	if _, isTx := c.c.q.(*sql.Tx); isTx {
+		return nil, errors.New("olympian: the migration already runs in a transaction, use GetDB without Begin")
+	}
+	if _, err := c.c.q.Exec("BEGIN"); err != nil {
+		return nil, err
+	}
+	return scopedTx{q: c.c.q}, nil
+}
+
+func (c *scopedConn) ExecContext(_ context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	if c.c.recorder != nil {
+		*c.c.recorder = append(*c.c.recorder, Operation{Kind: OpRawSQL, SQL: []string{query}})
+		return driver.RowsAffected(0), nil
+	}
+	result, err := c.c.q.Exec(query, namedValues(args)...)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c *scopedConn) QueryContext(_ context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	rows, err := c.c.q.Query(query, namedValues(args)...)
+	if err != nil {
+		return nil, err
+	}
+	columns, err := rows.Columns()
+	if err != nil {
+		_ = rows.Close()
+		return nil, err
+	}
+	return &scopedRows{rows: rows, columns: columns}, nil
+}
+
+func namedValues(args []driver.NamedValue) []interface{} {
+	values := make([]interface{}, len(args))
+	for i, arg := range args {
+		if arg.Name != "" {
+			values[i] = sql.Named(arg.Name, arg.Value)
+		} else {
+			values[i] = arg.Value
+		}
+	}
+	return values
+}
+
+type scopedStmt struct {
+	conn  *scopedConn
+	query string
+}
+
+func (s *scopedStmt) Close() error {
+	return nil
+}
+
+func (s *scopedStmt) NumInput() int {
+	return -1
+}
+
+func (s *scopedStmt) Exec(args []driver.Value) (driver.Result, error) {
+	return s.conn.ExecContext(context.Background(), s.query, driverValues(args))
+}
+
+func (s *scopedStmt) Query(args []driver.Value) (driver.Rows, error) {
+	return s.conn.QueryContext(context.Background(), s.query, driverValues(args))
+}
+
+func driverValues(args []driver.Value) []driver.NamedValue {
+	named := make([]driver.NamedValue, len(args))
+	for i, arg := range args {
+		named[i] = driver.NamedValue{Ordinal: i + 1, Value: arg}
+	}
+	return named
+}
+
+// scopedTx is a transaction begun on a single connection. Without one, it
+// lets migrations that begin their own transaction run in pretend mode,
+// where nothing is executed.
+type scopedTx struct {
+	q Querier
+}
+
+func (t scopedTx) Commit() error {
+	return t.end("COMMIT")
+}
+
+func (t scopedTx) Rollback() error {
+	return t.end("ROLLBACK")
+}
+
+func (t scopedTx) end(query string) error {
+	if t.q == nil {
+		return nil
+	}
+	_, err := t.q.Exec(query)
+	return err
+}
+
+// connQuerier runs statements on a single connection taken from the pool.
+type connQuerier struct {
+	c *sql.Conn
+}
+
+func (q connQuerier) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return q.c.ExecContext(context.Background(), query, args...)
+}
+
+func (q connQuerier) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	return q.c.QueryContext(context.Background(), query, args...)
+}
+
+func (q connQuerier) QueryRow(query string, args ...interface{}) *sql.Row {
+	return q.c.QueryRowContext(context.Background(), query, args...)
+}
+
+type scopedRows struct {
+	rows    *sql.Rows
+	columns []string
+}
+
+func (r *scopedRows) Columns() []string {
+	return r.columns
+}
+
+func (r *scopedRows) Close() error {
+	return r.rows.Close()
+}
+
+func (r *scopedRows) Next(dest []driver.Value) error {
+	if !r.rows.Next() {
+		if err := r.rows.Err(); err != nil {
+			return err
+		}
+		return io.EOF
+	}
+	values := make([]interface{}, len(dest))
+	pointers := make([]interface{}, len(dest))
+	for i := range values {
+		pointers[i] = &values[i]
+	}
+	if err := r.rows.Scan(pointers...); err != nil {
+		return err
+	}
+	for i, value := range values {
+		dest[i] = value
+	}
+	return nil
+}
@@ -0,0 +1,540 @@
+package olympian
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// needsRebuild reports whether a Modify call contains changes SQLite's ALTER
+// TABLE cannot express, which includes adding or dropping a foreign key or
+// CHECK constraint and adding a stored generated column.
+func (d *SQLiteDialect) needsRebuild(tb *TableBuilder) bool {
+	if len(tb.foreignKeys) > 0 || len(tb.droppedForeignKeys) > 0 ||
+		len(tb.checks) > 0 || len(tb.droppedChecks) > 0 {
+		return true
+	}
+	for _, col := range tb.columns {
+		if col.change || col.generatedAs != "" && !col.virtual {
+			return true
+		}
+	}
+	return false
+}
+
+// dropNeedsRebuild reports whether SQLite would refuse ALTER TABLE DROP
+// COLUMN, which it does for columns that are part of a primary key, an index,
+// a unique or foreign key constraint, or a CHECK constraint.
+func (d *SQLiteDialect) dropNeedsRebuild(tb *TableBuilder, columnName string) (bool, error) {
+	if tb.plannedTable(tb.tableName) {
+		return true, nil
+	}
+	q, tableName := tb.db, tb.tableName
+	table, err := describeTable(q, d, tableName)
+	if err != nil {
+		return false, fmt.Errorf("failed to inspect table %s: %w", tableName, err)
+	}
+
+	if col, ok := table.Column(columnName); ok && col.Primary {
+		return true, nil
+	}
+	for _, index := range table.Indexes {
+		if containsString(index.Columns, columnName) {
+			return true, nil
+		}
+	}
+	for _, fk := range table.ForeignKeys {
+		if containsString(fk.Columns, columnName) {
+			return true, nil
+		}
+	}
+
+	var createSQL string
+	err = q.QueryRow(`SELECT sql FROM sqlite_master WHERE type = 'table' AND name = ?`, tableName).Scan(&createSQL)
+	if err != nil {
+		return false, fmt.Errorf("failed to read definition of table %s: %w", tableName, err)
+	}
+	items, _, err := splitTableDefinition(createSQL)
+	if err != nil {
+		return false, err
+	}
+	for _, item := range items {
+		name, isColumn := sqliteColumnName(item)
+		if isColumn && strings.EqualFold(name, columnName) {
+			continue
+		}
+		if i := topLevelKeyword(item, "CHECK"); i >= 0 && mentionsIdentifier(item[i:], columnName) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// rebuildTable applies changes SQLite's ALTER TABLE cannot express, following
+// the procedure from https://www.sqlite.org/lang_altertable.html: a new table
+// is created from the stored CREATE TABLE statement with the changes applied,
+// the rows are copied over, the old table is dropped and the new one renamed
+// in its place. Indexes, triggers and the views using the table are then
+// recreated. Columns in drop are removed along with the indexes that use
+// them; table constraints using them are an error. Foreign keys and CHECK constraints of tb are added
+// and those named in tb.droppedForeignKeys and tb.droppedChecks removed.
+func (d *SQLiteDialect) rebuildTable(tb *TableBuilder, drop []string) ([]string, error) {
+	if tb.plannedTable(tb.tableName) {
+		return plannedRebuild(tb.tableName), nil
+	}
+	var createSQL string
+	err := tb.db.QueryRow(`SELECT sql FROM sqlite_master WHERE type = 'table' AND name = ?`, tb.tableName).Scan(&createSQL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read definition of table %s: %w", tb.tableName, err)
+	}
+
+	items, suffix, err := splitTableDefinition(createSQL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse definition of table %s: %w", tb.tableName, err)
+	}
+
+	changed := make(map[string]*Column)
+	for _, col := range tb.columns {
+		if col.change {
+			changed[strings.ToLower(col.name)] = col
+		}
+	}
+
+	dropped := func(text string) (string, bool) {
+		for _, name := range drop {
+			if mentionsIdentifier(text, name) {
+				return name, true
+			}
+		}
+		return "", false
+	}
+
+	removed := make(map[string]bool)
+	var columns, definitions, constraints []string
+	for _, item := range items {
+		name, ok := sqliteColumnName(item)
+		if !ok {
+			if fk, isForeign := foreignKeyConstraintName(tb.tableName, item); isForeign && containsString(tb.droppedForeignKeys, fk) {
+				removed[fk] = true
+				continue
+			}
+			if check, isCheck := checkConstraintName(item); isCheck && containsString(tb.droppedChecks, check) {
+				removed[check] = true
+				continue
+			}
+			// Like SQLite's own DROP COLUMN, refuse to silently lose a primary
+			// key, unique, foreign key or CHECK constraint that spans the
+			// column.
+			if column, ok := dropped(item); ok {
+				return nil, fmt.Errorf("cannot drop column %s of table %s, it is used by the constraint %s; drop or redefine the constraint first",
+					column, tb.tableName, strings.Join(strings.Fields(item), " "))
+			}
+			constraints = append(constraints, item)
+			continue
+		}
+		if containsString(drop, name) {
+			continue
+		}
+		// Generated columns are computed again in the new table.
+		if !generatedDefinition(item) {
+			columns = append(columns, d.QuoteIdentifier(name))
+		}
+		if col, ok := changed[strings.ToLower(name)]; ok {
+			item = d.changedColumnDefinition(col, item)
+		}
+		definitions = append(definitions, item)
+	}
+	for _, col := range tb.columns {
+		if !col.change {
+			definitions = append(definitions, d.columnDefinition(col))
+		}
+	}
+	for _, name := range tb.droppedForeignKeys {
+		if !removed[name] {
+			return nil, fmt.Errorf("table %s has no foreign key %s", tb.tableName, name)
+		}
+	}
+	for _, name := range tb.droppedChecks {
+		if !removed[name] {
+			return nil, fmt.Errorf("table %s has no CHECK constraint %s", tb.tableName, name)
+		}
+	}
+	for _, fk := range tb.foreignKeys {
+		constraints = append(constraints, foreignKeyDefinition(d, tb.tableName, fk, fk.name != ""))
+	}
+	for _, check := range tb.checks {
+		constraints = append(constraints, checkDefinition(d, check))
+	}
+
+	indexes, err := queryStrings(tb.db, `SELECT sql FROM sqlite_master
+		WHERE type = 'index' AND tbl_name = ? AND sql IS NOT NULL
+		ORDER BY name`, tb.tableName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read indexes of table %s: %w", tb.tableName, err)
+	}
+	triggers, err := queryStrings(tb.db, `SELECT sql FROM sqlite_master
+		WHERE type = 'trigger' AND tbl_name = ?
+		ORDER BY name`, tb.tableName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read triggers of table %s: %w", tb.tableName, err)
+	}
+	views, err := d.dependentViews(tb.db, tb.tableName)
+	if err != nil {
+		return nil, err
+	}
+
+	table := d.QuoteIdentifier(tb.tableName)
+	rebuilt := d.QuoteIdentifier("olympian_rebuild_" + tb.tableName)
+	copied := strings.Join(columns, ", ")
+
+	var sqls []string
+	for _, view := range views {
+		sqls = append(sqls, fmt.Sprintf("DROP VIEW %s", d.QuoteIdentifier(view.name)))
+	}
+	sqls = append(sqls,
+		fmt.Sprintf("CREATE TABLE %s (\n  %s\n)%s", rebuilt, strings.Join(append(definitions, constraints...), ",\n  "), suffix),
+		fmt.Sprintf("INSERT INTO %s (%s) SELECT %s FROM %s", rebuilt, copied, copied, table),
+		fmt.Sprintf("DROP TABLE %s", table),
+		fmt.Sprintf("ALTER TABLE %s RENAME TO %s", rebuilt, table),
+	)
+	for _, index := range indexes {
+		if i := strings.Index(index, "("); i >= 0 {
+			if _, ok := dropped(index[i:]); ok {
+				continue
+			}
+		}
+		sqls = append(sqls, index)
+	}
+	sqls = append(sqls, triggers...)
+	for _, view := range views {
+		sqls = append(sqls, view.sql)
+	}
+	return sqls, nil
+}
+
+type sqliteView struct {
+	name string
+	sql  string
+}
+
+func (d *SQLiteDialect) dependentViews(q Querier, tableName string) ([]sqliteView, error) {
+	rows, err := q.Query(`SELECT name, sql FROM sqlite_master WHERE type = 'view' ORDER BY name`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read views: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var views []sqliteView
+	for rows.Next() {
+		var view sqliteView
+		if err := rows.Scan(&view.name, &view.sql); err != nil {
+			return nil, err
+		}
+		if i := topLevelKeyword(view.sql, "AS"); i >= 0 && mentionsIdentifier(view.sql[i:], tableName) {
+			views = append(views, view)
+		}
+	}
+	return views, rows.Err()
+}
+
+// runRebuild executes the statements of rebuildTable in a transaction with
+// foreign key enforcement off, then runs foreign_key_check. Inside a
+// migration the migrator has already switched enforcement off.
+func (d *SQLiteDialect) runRebuild(q Querier, sqls []string) error {
+	db, ok := q.(*sql.DB)
+	if !ok {
+		var enabled bool
+		if err := q.QueryRow("PRAGMA foreign_keys").Scan(&enabled); err != nil {
+			return err
+		}
+		if enabled {
+			return fmt.Errorf("cannot rebuild a table inside a transaction while foreign keys are enforced")
+		}
+		for _, query := range sqls {
+			if _, err := q.Exec(query); err != nil {
+				return err
+			}
+		}
+		return checkSQLiteForeignKeys(q)
+	}
+
+	ctx := context.Background()
+	c, err := db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = c.Close() }()
+
+	var enabled bool
+	if err := c.QueryRowContext(ctx, "PRAGMA foreign_keys").Scan(&enabled); err != nil {
+		return err
+	}
+	if enabled {
+		if _, err := c.ExecContext(ctx, "PRAGMA foreign_keys = OFF"); err != nil {
+			return err
+		}
+		defer func() { _, _ = c.ExecContext(ctx, "PRAGMA foreign_keys = ON") }()
+	}
+
+	tx, err := c.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if err := d.runRebuild(tx, sqls); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+func checkSQLiteForeignKeys(q Querier) error {
+	rows, err := q.Query("PRAGMA foreign_key_check")
+	if err != nil {
+		return fmt.Errorf("failed to check foreign keys: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	if rows.Next() {
+		var table, parent string
+		var rowid sql.NullInt64
+		var fkid int
+		if err := rows.Scan(&table, &rowid, &parent, &fkid); err != nil {
+			return err
+		}
+		return fmt.Errorf("foreign key violation: row %d of %s references a missing row in %s", rowid.Int64, table, parent)
+	}
+	return rows.Err()
+}
+
+// foreignKeyConstraintName returns the name of a FOREIGN KEY table
+// constraint. Unnamed constraints get the default name Foreign would have
+// given them.
+func foreignKeyConstraintName(tableName, item string) (string, bool) {
+	fk := topLevelKeyword(item, "FOREIGN")
+	if fk < 0 {
+		return "", false
+	}
+
+	fields := strings.Fields(item[:fk])
+	if len(fields) == 2 && strings.EqualFold(fields[0], "CONSTRAINT") {
+		return strings.Trim(fields[1], "\"`[]"), true
+	}
+
+	open := strings.Index(item[fk:], "(")
+	end := strings.Index(item[fk:], ")")
+	if open < 0 || end < open {
+		return "", false
+	}
+	var columns []string
+	for _, column := range strings.Split(item[fk+open+1:fk+end], ",") {
+		columns = append(columns, strings.Trim(strings.TrimSpace(column), "\"`[]"))
+	}
+	return foreignKeyName(tableName, columns), true
+}
+
+// checkConstraintName returns the name of a named CHECK table constraint.
+func checkConstraintName(item string) (string, bool) {
+	check := topLevelKeyword(item, "CHECK")
+	if check < 0 {
+		return "", false
+	}
+	fields := strings.Fields(item[:check])
+	if len(fields) == 2 && strings.EqualFold(fields[0], "CONSTRAINT") {
+		return strings.Trim(fields[1], "\"`[]"), true
+	}
+	return "", false
+}
+
+// changedColumnDefinition builds the new definition of a changed column,
+// keeping the constraints of the original definition that Change does not
+// touch.
+func (d *SQLiteDialect) changedColumnDefinition(col *Column, original string) string {
+	def := fmt.Sprintf("%s %s%s", d.QuoteIdentifier(col.name), d.GetDataType(col), collationClause(d, col))
+
+	primary := topLevelKeyword(original, "PRIMARY") >= 0
+	if primary {
+		def += " PRIMARY KEY"
+		if topLevelKeyword(original, "AUTOINCREMENT") >= 0 {
+			def += " AUTOINCREMENT"
+		}
+	}
+	if !col.nullable {
+		def += " NOT NULL"
+	}
+	if !primary && (col.unique || topLevelKeyword(original, "UNIQUE") >= 0) {
+		def += " UNIQUE"
+	}
+	if col.defaultValue != nil {
+		def += fmt.Sprintf(" DEFAULT %s", columnDefault(d, col))
+	} else if col.existing != nil && col.existing.Default != nil {
+		def += fmt.Sprintf(" DEFAULT %s", *col.existing.Default)
+	}
+
+	// Inline CHECK, REFERENCES and COLLATE clauses are carried over as is,
+	// except the CHECK of an enum, which is rebuilt from the new values, and
+	// a COLLATE replaced by Collation.
+	keywords := []string{"CHECK", "REFERENCES", "COLLATE"}
+	if col.dataType == "enum" {
+		keywords = keywords[1:]
+		def += d.enumCheck(col)
+	}
+	tail := -1
+	for _, keyword := range keywords {
+		if i := topLevelKeyword(original, keyword); i >= 0 && (tail < 0 || i < tail) {
+			tail = i
+		}
+	}
+	if tail >= 0 {
+		rest := original[tail:]
+		if col.dataType == "enum" {
+			if i := topLevelKeyword(rest, "CHECK"); i >= 0 {
+				rest = strings.TrimSpace(rest[:i])
+			}
+		}
+		if col.collation != "" {
+			rest = removeCollate(rest)
+		}
+		if rest != "" {
+			def += " " + rest
+		}
+	}
+	return def
+}
+
+// removeCollate removes a COLLATE clause and its collation name from the
+// tail of a column definition.
+func removeCollate(def string) string {
+	i := topLevelKeyword(def, "COLLATE")
+	if i < 0 {
+		return def
+	}
+	fields := strings.Fields(def[i:])
+	rest := ""
+	if len(fields) > 2 {
+		rest = strings.Join(fields[2:], " ")
+	}
+	return strings.TrimSpace(strings.TrimSpace(def[:i]) + " " + rest)
+}
+
+// splitTableDefinition splits a CREATE TABLE statement into its column
+// definitions and table constraints, plus any table options after the
+// closing parenthesis such as WITHOUT ROWID.
+func splitTableDefinition(createSQL string) ([]string, string, error) {
+	var items []string
+	start, depth := -1, 0
+	var quote byte
+
+	for i := 0; i < len(createSQL); i++ {
+		c := createSQL[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case c == '[':
+			quote = ']'
+		case c == '(':
+			depth++
+			if depth == 1 {
+				start = i + 1
+			}
+		case c == ')':
+			depth--
+			if depth == 0 {
+				items = append(items, strings.TrimSpace(createSQL[start:i]))
+				return items, createSQL[i+1:], nil
+			}
+		case c == ',' && depth == 1:
+			items = append(items, strings.TrimSpace(createSQL[start:i]))
+			start = i + 1
+		}
+	}
+	return nil, "", fmt.Errorf("unbalanced parentheses in %q", createSQL)
+}
+
+// sqliteColumnName returns the column name of a column definition, or false
+// for a table constraint.
+func sqliteColumnName(item string) (string, bool) {
+	fields := strings.Fields(item)
+	if len(fields) == 0 {
+		return "", false
+	}
+
+	switch strings.ToUpper(fields[0]) {
+	case "CONSTRAINT", "PRIMARY", "UNIQUE", "CHECK", "FOREIGN":
+		return "", false
+	}
+
+	name := fields[0]
+	if len(name) >= 2 && strings.ContainsRune("\"`[", rune(name[0])) {
+		end := byte(name[0])
+		if end == '[' {
+			end = ']'
+		}
+		if i := strings.IndexByte(item[1:], end); i >= 0 {
+			return item[1 : i+1], true
+		}
+	}
+	return name, true
+}
+
+// generatedDefinition reports whether a column definition declares a
+// generated column, with or without GENERATED ALWAYS.
+func generatedDefinition(item string) bool {
+	return topLevelKeyword(item, "AS") >= 0
+}
+
+// topLevelKeyword returns the position of keyword in a column definition,
+// ignoring quoted text and anything inside parentheses, or -1.
+func topLevelKeyword(def, keyword string) int {
+	depth := 0
+	var quote byte
+
+	for i := 0; i < len(def); i++ {
+		c := def[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case c == '[':
+			quote = ']'
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+		case depth == 0 && (i == 0 || !isIdentifierByte(def[i-1])):
+			end := i + len(keyword)
+			if end <= len(def) && strings.EqualFold(def[i:end], keyword) && (end == len(def) || !isIdentifierByte(def[end])) {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// mentionsIdentifier reports whether name appears in text as a whole
+// identifier, bare or quoted.
+func mentionsIdentifier(text, name string) bool {
+	lower, name := strings.ToLower(text), strings.ToLower(name)
+	for i := 0; ; {
+		j := strings.Index(lower[i:], name)
+		if j < 0 {
+			return false
+		}
+		start, end := i+j, i+j+len(name)
+		if (start == 0 || !isIdentifierByte(lower[start-1])) && (end == len(lower) || !isIdentifierByte(lower[end])) {
+			return true
+		}
+		i = end
+	}
+}
+
+func isIdentifierByte(c byte) bool {
+	return c == '_' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
@@ -0,0 +1,478 @@
+package olympian
+
+import (
+	"database/sql"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestChangeColumnSQLite(t *testing.T) {
+	db := setupTestDB(t)
+	defer func() { _ = db.Close() }()
+
+	SetDB(db, &SQLiteDialect{})
+
+	if err := Table("users").Create(func() {
+		Uuid("id").Primary()
+		String("name")
+		String("email").Unique()
+		String("status").Default("active")
+		Integer("age").Nullable()
+	}); err != nil {
+		t.Fatalf("Failed to create table: %v", err)
+	}
+	if err := CreateIndex("users", []string{"name"}, "idx_users_name"); err != nil {
+		t.Fatalf("Failed to create index: %v", err)
+	}
+	if _, err := db.Exec("INSERT INTO users (id, name, email, age) VALUES ('1', 'Ada', 'ada@example.com', 36)"); err != nil {
+		t.Fatalf("Failed to insert user: %v", err)
+	}
+
+	if err := Table("users").Modify(func() {
+		String("name").Nullable().Change()
+		Text("status").Change()
+		BigInteger("age").Nullable(false).Default(0).Change()
+		String("phone").Nullable()
+	}); err != nil {
+		t.Fatalf("Failed to change columns: %v", err)
+	}
+
+	table, err := DescribeTable("users")
+	if err != nil {
+		t.Fatalf("Failed to describe table: %v", err)
+	}
+
+	if name, _ := table.Column("name"); !name.Nullable {
+		t.Error("Expected name to be nullable")
+	}
+	if status, _ := table.Column("status"); status.Nullable || status.Default == nil || *status.Default != "'active'" {
+		t.Errorf("Expected status to keep NOT NULL and its default, got %+v", status)
+	}
+	if age, _ := table.Column("age"); age.Nullable || age.Default == nil || *age.Default != "0" {
+		t.Errorf("Expected age to be NOT NULL with default 0, got %+v", age)
+	}
+	if _, ok := table.Column("phone"); !ok {
+		t.Error("Expected phone to be added")
+	}
+	if id, _ := table.Column("id"); !id.Primary {
+		t.Error("Expected id to remain the primary key")
+	}
+
+	indexes := make(map[string]bool)
+	for _, index := range table.Indexes {
+		for _, column := range index.Columns {
+			indexes[column] = indexes[column] || index.Unique
+		}
+		if index.Name == "idx_users_name" {
+			indexes["idx_users_name"] = true
+		}
+	}
+	if !indexes["idx_users_name"] || !indexes["email"] {
+		t.Errorf("Expected indexes to be kept, got %+v", table.Indexes)
+	}
+
+	var email string
+	if err := db.QueryRow("SELECT email FROM users WHERE id = '1'").Scan(&email); err != nil || email != "ada@example.com" {
+		t.Errorf("Expected rows to be copied, got %q (%v)", email, err)
+	}
+}
+
+func TestChangeMissingColumn(t *testing.T) {
+	db := setupTestDB(t)
+	defer func() { _ = db.Close() }()
+
+	SetDB(db, &SQLiteDialect{})
+
+	if err := Table("users").Create(func() {
+		Uuid("id").Primary()
+	}); err != nil {
+		t.Fatalf("Failed to create table: %v", err)
+	}
+
+	if err := Table("users").Modify(func() {
+		String("name").Change()
+	}); err == nil {
+		t.Error("Expected changing a missing column to fail")
+	}
+}
+
+func TestSplitTableDefinition(t *testing.T) {
+	items, suffix, err := splitTableDefinition(`CREATE TABLE "users" (
+  id TEXT PRIMARY KEY NOT NULL,
+  price DECIMAL(10,2) DEFAULT 'a, b',
+  CHECK (price > 0),
+  FOREIGN KEY (id) REFERENCES accounts(id)
+) WITHOUT ROWID`)
+	if err != nil {
+		t.Fatalf("Failed to split definition: %v", err)
+	}
+
+	expected := []string{
+		"id TEXT PRIMARY KEY NOT NULL",
+		"price DECIMAL(10,2) DEFAULT 'a, b'",
+		"CHECK (price > 0)",
+		"FOREIGN KEY (id) REFERENCES accounts(id)",
+	}
+	if len(items) != len(expected) {
+		t.Fatalf("Expected %d items, got %d: %q", len(expected), len(items), items)
+	}
+	for i := range expected {
+		if items[i] != expected[i] {
+			t.Errorf("Expected %q, got %q", expected[i], items[i])
+		}
+	}
+	if suffix != " WITHOUT ROWID" {
+		t.Errorf("Expected WITHOUT ROWID suffix, got %q", suffix)
+	}
+
+	if name, ok := sqliteColumnName(`"order date" TEXT`); !ok || name != "order date" {
+		t.Errorf("Expected quoted column name, got %q", name)
+	}
+	if _, ok := sqliteColumnName("CONSTRAINT fk FOREIGN KEY (a) REFERENCES b(id)"); ok {
+		t.Error("Expected a table constraint")
+	}
+}
+
+func TestDropIndexedColumnSQLite(t *testing.T) {
+	db := setupTestDB(t)
+	defer func() { _ = db.Close() }()
+
+	SetDB(db, &SQLiteDialect{})
+
+	if err := Table("users").Create(func() {
+		Uuid("id").Primary()
+		String("name")
+		String("email").Unique()
+		String("legacy").Nullable()
+	}); err != nil {
+		t.Fatalf("Failed to create table: %v", err)
+	}
+	if err := CreateIndex("users", []string{"name"}, "idx_users_name"); err != nil {
+		t.Fatalf("Failed to create index: %v", err)
+	}
+	if err := CreateIndex("users", []string{"name", "legacy"}, "idx_users_name_legacy"); err != nil {
+		t.Fatalf("Failed to create index: %v", err)
+	}
+	if _, err := db.Exec(`CREATE TRIGGER users_touch AFTER UPDATE ON users BEGIN SELECT 1; END`); err != nil {
+		t.Fatalf("Failed to create trigger: %v", err)
+	}
+	if _, err := db.Exec(`CREATE VIEW user_names AS SELECT name FROM users`); err != nil {
+		t.Fatalf("Failed to create view: %v", err)
+	}
+	if _, err := db.Exec("INSERT INTO users (id, name, email) VALUES ('1', 'Ada', 'ada@example.com')"); err != nil {
+		t.Fatalf("Failed to insert user: %v", err)
+	}
+
+	for _, column := range []string{"email", "legacy"} {
+		if err := Table("users").DropColumn(column); err != nil {
+			t.Fatalf("Failed to drop %s: %v", column, err)
+		}
+	}
+
+	table, err := DescribeTable("users")
+	if err != nil {
+		t.Fatalf("Failed to describe table: %v", err)
+	}
+	if len(table.Columns) != 2 {
+		t.Errorf("Expected id and name to remain, got %+v", table.Columns)
+	}
+	if len(table.Indexes) != 2 {
+		t.Errorf("Expected the primary key and idx_users_name to remain, got %+v", table.Indexes)
+	}
+
+	var count int
+	if err := db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE name IN ('users_touch', 'user_names')").Scan(&count); err != nil || count != 2 {
+		t.Errorf("Expected trigger and view to be recreated, got %d (%v)", count, err)
+	}
+	if err := db.QueryRow("SELECT COUNT(*) FROM user_names").Scan(&count); err != nil || count != 1 {
+		t.Errorf("Expected the view to return the copied row, got %d (%v)", count, err)
+	}
+}
+
+func TestDropConstrainedColumnSQLite(t *testing.T) {
+	db := setupTestDB(t)
+	defer func() { _ = db.Close() }()
+
+	SetDB(db, &SQLiteDialect{})
+
+	if err := Table("memberships").Create(func() {
+		Uuid("user_id")
+		Uuid("team_id")
+		String("role")
+		PrimaryKey("user_id", "team_id")
+	}); err != nil {
+		t.Fatalf("Failed to create table: %v", err)
+	}
+	if _, err := db.Exec(`CREATE TABLE seats (row TEXT, number INTEGER, UNIQUE (row, number))`); err != nil {
+		t.Fatalf("Failed to create table: %v", err)
+	}
+
+	tests := []struct {
+		table, column, constraint string
+	}{
+		{"memberships", "team_id", "PRIMARY KEY"},
+		{"seats", "number", "UNIQUE"},
+	}
+	for _, tt := range tests {
+		err := Table(tt.table).DropColumn(tt.column)
+		if err == nil || !strings.Contains(err.Error(), tt.constraint) {
+			t.Errorf("Expected dropping %s.%s to fail on its %s constraint, got %v", tt.table, tt.column, tt.constraint, err)
+		}
+		if exists, err := HasColumn(tt.table, tt.column); err != nil || !exists {
+			t.Errorf("Expected %s.%s to remain: %v", tt.table, tt.column, err)
+		}
+	}
+
+	if err := Table("memberships").DropColumn("role"); err != nil {
+		t.Errorf("Failed to drop an unconstrained column: %v", err)
+	}
+}
+
+func TestRebuildKeepsReferencingRows(t *testing.T) {
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "app.db")+"?_foreign_keys=on")
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	migrator := NewMigrator(db, &SQLiteDialect{})
+	if err := migrator.Init(); err != nil {
+		t.Fatalf("Failed to initialize migrator: %v", err)
+	}
+
+	migrations := []Migration{
+		{
+			Name: "1_create_tables",
+			Up: func() error {
+				if err := Table("users").Create(func() {
+					Integer("id").Primary()
+					String("name")
+				}); err != nil {
+					return err
+				}
+				return Table("posts").Create(func() {
+					Integer("id").Primary()
+					Integer("user_id")
+					Foreign("user_id").References("id").On("users").OnDelete("cascade")
+				})
+			},
+			Down: func() error { return nil },
+		},
+	}
+	if err := migrator.Migrate(migrations); err != nil {
+		t.Fatalf("Failed to run migrations: %v", err)
+	}
+	if _, err := db.Exec("INSERT INTO users (id, name) VALUES (1, 'Ada'); INSERT INTO posts (id, user_id) VALUES (1, 1)"); err != nil {
+		t.Fatalf("Failed to insert rows: %v", err)
+	}
+
+	migrations = append(migrations, Migration{
+		Name: "2_change_users_name",
+		Up: func() error {
+			return Table("users").Modify(func() {
+				Text("name").Nullable().Change()
+			})
+		},
+		Down: func() error { return nil },
+	})
+	if err := migrator.Migrate(migrations); err != nil {
+		t.Fatalf("Failed to run migrations: %v", err)
+	}
+
+	// Outside a migration the rebuild manages its own transaction.
+	SetDB(db, &SQLiteDialect{})
+	if err := Table("users").Modify(func() {
+		String("name").Change()
+	}); err != nil {
+		t.Fatalf("Failed to change column: %v", err)
+	}
+
+	var posts int
+	if err := db.QueryRow("SELECT COUNT(*) FROM posts").Scan(&posts); err != nil || posts != 1 {
+		t.Errorf("Expected the post to survive the rebuilds, got %d (%v)", posts, err)
+	}
+
+	var enabled bool
+	if err := db.QueryRow("PRAGMA foreign_keys").Scan(&enabled); err != nil || !enabled {
+		t.Errorf("Expected foreign keys to be enforced again (%v)", err)
+	}
+}
+
+func TestCheckSQLiteForeignKeys(t *testing.T) {
+	db := setupTestDB(t)
+	defer func() { _ = db.Close() }()
+
+	for _, query := range []string{
+		"CREATE TABLE users (id INTEGER PRIMARY KEY)",
+		"CREATE TABLE posts (id INTEGER PRIMARY KEY, user_id INTEGER REFERENCES users(id))",
+		"INSERT INTO posts (id, user_id) VALUES (1, 42)",
+	} {
+		if _, err := db.Exec(query); err != nil {
+			t.Fatalf("Failed to run %s: %v", query, err)
+		}
+	}
+
+	if err := checkSQLiteForeignKeys(db); err == nil || !strings.Contains(err.Error(), "posts") {
+		t.Errorf("Expected a foreign key violation in posts, got %v", err)
+	}
+}
+
+func TestAddAndDropForeignKeySQLite(t *testing.T) {
+	db := setupTestDB(t)
+	defer func() { _ = db.Close() }()
+
+	SetDB(db, &SQLiteDialect{})
+
+	if err := Table("users").Create(func() {
+		Integer("id").Primary()
+	}); err != nil {
+		t.Fatalf("Failed to create users table: %v", err)
+	}
+	if err := Table("posts").Create(func() {
+		Integer("id").Primary()
+	}); err != nil {
+		t.Fatalf("Failed to create posts table: %v", err)
+	}
+	if _, err := db.Exec("INSERT INTO users (id) VALUES (1); INSERT INTO posts (id) VALUES (1)"); err != nil {
+		t.Fatalf("Failed to insert rows: %v", err)
+	}
+
+	if err := Table("posts").Modify(func() {
+		Integer("author_id").Nullable()
+		Integer("editor_id").Nullable()
+		Foreign("author_id").References("id").On("users")
+		Foreign("editor_id").References("id").On("users").Name("posts_editor_fk")
+	}); err != nil {
+		t.Fatalf("Failed to add foreign keys: %v", err)
+	}
+
+	foreignKeys, err := GetForeignKeys("posts")
+	if err != nil {
+		t.Fatalf("Failed to read foreign keys: %v", err)
+	}
+	if len(foreignKeys) != 2 {
+		t.Fatalf("Expected 2 foreign keys, got %+v", foreignKeys)
+	}
+
+	if err := Table("posts").DropForeign("fk_posts_author_id"); err != nil {
+		t.Fatalf("Failed to drop unnamed foreign key: %v", err)
+	}
+	if err := Table("posts").DropForeign("posts_editor_fk"); err != nil {
+		t.Fatalf("Failed to drop named foreign key: %v", err)
+	}
+	if foreignKeys, _ := GetForeignKeys("posts"); len(foreignKeys) != 0 {
+		t.Errorf("Expected no foreign keys, got %+v", foreignKeys)
+	}
+	if err := Table("posts").DropForeign("posts_editor_fk"); err == nil {
+		t.Error("Expected an error dropping a missing foreign key")
+	}
+
+	var posts int
+	if err := db.QueryRow("SELECT COUNT(*) FROM posts").Scan(&posts); err != nil || posts != 1 {
+		t.Errorf("Expected the post to survive the rebuilds, got %d (%v)", posts, err)
+	}
+}
+
+func TestForeignKeyValidation(t *testing.T) {
+	db := setupTestDB(t)
+	defer func() { _ = db.Close() }()
+
+	SetDB(db, &SQLiteDialect{})
+
+	err := Table("posts").Create(func() {
+		Integer("id").Primary()
+		Integer("author_id")
+		Foreign("author_id").References("id", "tenant_id").On("users")
+	})
+	if err == nil {
+		t.Error("Expected an error for mismatched foreign key columns")
+	}
+
+	err = Table("posts").Create(func() {
+		Integer("id").Primary()
+		Integer("author_id")
+		Foreign("author_id").References("id")
+	})
+	if err == nil {
+		t.Error("Expected an error for a foreign key without On")
+	}
+}
+
+func TestCheckConstraintsSQLite(t *testing.T) {
+	db := setupTestDB(t)
+	defer func() { _ = db.Close() }()
+
+	SetDB(db, &SQLiteDialect{})
+
+	if err := Table("products").Create(func() {
+		Integer("id").Primary()
+		Integer("price").Check("price >= 0")
+		String("status")
+		Check("products_status_check", "status IN ('draft', 'live')")
+	}); err != nil {
+		t.Fatalf("Failed to create table: %v", err)
+	}
+	if _, err := db.Exec("INSERT INTO products (id, price, status) VALUES (1, -1, 'draft')"); err == nil {
+		t.Error("Expected the column CHECK to reject a negative price")
+	}
+	if _, err := db.Exec("INSERT INTO products (id, price, status) VALUES (1, 5, 'live')"); err != nil {
+		t.Fatalf("Failed to insert product: %v", err)
+	}
+
+	if err := Table("products").Modify(func() {
+		DropCheck("products_status_check")
+		Check("products_price_check", "price < 1000")
+	}); err != nil {
+		t.Fatalf("Failed to modify checks: %v", err)
+	}
+	if _, err := db.Exec("INSERT INTO products (id, price, status) VALUES (2, 5, 'archived')"); err != nil {
+		t.Errorf("Expected the dropped CHECK to no longer apply: %v", err)
+	}
+	if _, err := db.Exec("INSERT INTO products (id, price, status) VALUES (3, 5000, 'live')"); err == nil {
+		t.Error("Expected the added CHECK to reject a large price")
+	}
+
+	if err := Table("products").Modify(func() {
+		DropCheck("products_status_check")
+	}); err == nil {
+		t.Error("Expected an error dropping a missing CHECK constraint")
+	}
+}
+
+func TestEnumSQLite(t *testing.T) {
+	db := setupTestDB(t)
+	defer func() { _ = db.Close() }()
+
+	SetDB(db, &SQLiteDialect{})
+
+	if err := Table("orders").Create(func() {
+		Integer("id").Primary()
+		Enum("status", []string{"pending", "shipped"}).Default("pending")
+	}); err != nil {
+		t.Fatalf("Failed to create table: %v", err)
+	}
+	if _, err := db.Exec("INSERT INTO orders (id, status) VALUES (1, 'packed')"); err == nil {
+		t.Error("Expected the enum CHECK to reject an unknown value")
+	}
+	if _, err := db.Exec("INSERT INTO orders (id) VALUES (1)"); err != nil {
+		t.Fatalf("Failed to insert order: %v", err)
+	}
+
+	if err := Table("orders").Modify(func() {
+		Enum("status", []string{"pending", "packed", "shipped"}).Change()
+	}); err != nil {
+		t.Fatalf("Failed to add enum value: %v", err)
+	}
+	if _, err := db.Exec("INSERT INTO orders (id, status) VALUES (2, 'packed')"); err != nil {
+		t.Errorf("Expected the new enum value to be accepted: %v", err)
+	}
+
+	var createSQL string
+	if err := db.QueryRow("SELECT sql FROM sqlite_master WHERE name = 'orders'").Scan(&createSQL); err != nil {
+		t.Fatalf("Failed to read table definition: %v", err)
+	}
+	if strings.Count(createSQL, "CHECK") != 1 || !strings.Contains(createSQL, "DEFAULT 'pending'") {
+		t.Errorf("Expected a single enum CHECK and the kept default in %s", createSQL)
+	}
+}
@@ -0,0 +1,144 @@
+package olympian
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Engine sets the storage engine of the table. It only applies to MySQL.
+func (tb *TableBuilder) Engine(engine string) *TableBuilder {
+	tb.engine = engine
+	return tb
+}
+
+// Charset sets the default character set of the table. It only applies to
+// MySQL.
+func (tb *TableBuilder) Charset(charset string) *TableBuilder {
+	tb.charset = charset
+	return tb
+}
+
+// Collation sets the default collation of the table. It only applies to
+// MySQL.
+func (tb *TableBuilder) Collation(collation string) *TableBuilder {
+	tb.collation = collation
+	return tb
+}
+
+// RowFormat sets ROW_FORMAT=DYNAMIC|COMPACT|COMPRESSED|REDUNDANT|FIXED. It
+// only applies to MySQL.
+func (tb *TableBuilder) RowFormat(format string) *TableBuilder {
+	tb.rowFormat = strings.ToUpper(format)
+	return tb
+}
+
+// Charset sets the character set of the column. Only MySQL supports it.
+func (cb *ColumnBuilder) Charset(charset string) *ColumnBuilder {
+	cb.column.charset = charset
+	return cb
+}
+
+// Collation sets the collation of the column.
+func (cb *ColumnBuilder) Collation(collation string) *ColumnBuilder {
+	cb.column.collation = collation
+	return cb
+}
+
+func (tb *TableBuilder) validateTableOptions() error {
+	switch tb.rowFormat {
+	case "", "DEFAULT", "DYNAMIC", "COMPACT", "COMPRESSED", "REDUNDANT", "FIXED":
+	default:
+		return fmt.Errorf("table %s: unsupported row format %q, expected DYNAMIC, COMPACT, COMPRESSED, REDUNDANT or FIXED", tb.tableName, tb.rowFormat)
+	}
+	if _, isMySQL := tb.dialect.(*MySQLDialect); isMySQL {
+		return nil
+	}
+	for _, col := range tb.columns {
+		if col.charset != "" {
+			return fmt.Errorf("column %s.%s: only MySQL supports column character sets, use Collation instead", tb.tableName, col.name)
+		}
+	}
+	return nil
+}
+
+// createTableOptions renders the options after the column list of CREATE
+// TABLE. Options set on the table win over the defaults of the dialect.
+func (d *MySQLDialect) createTableOptions(tb *TableBuilder) string {
+	engine := firstNonEmpty(tb.engine, d.Engine, "InnoDB")
+	charset := firstNonEmpty(tb.charset, d.Charset, "utf8mb4")
+	options := fmt.Sprintf("ENGINE=%s DEFAULT CHARSET=%s", engine, charset)
+
+	if collation := firstNonEmpty(tb.collation, d.Collation); collation != "" {
+		options += " COLLATE=" + collation
+	}
+	if format := firstNonEmpty(tb.rowFormat, strings.ToUpper(d.RowFormat)); format != "" {
+		options += " ROW_FORMAT=" + format
+	}
+	if tb.comment != "" {
+		options += " COMMENT=" + quoteLiteral(d, tb.comment)
+	}
+	return options
+}
+
+// alterTableOptions returns the table options set explicitly on tb, for
+// Modify.
+func (d *MySQLDialect) alterTableOptions(tb *TableBuilder) []string {
+	var options []string
+	if tb.engine != "" {
+		options = append(options, "ENGINE="+tb.engine)
+	}
+	if tb.charset != "" {
+		options = append(options, "DEFAULT CHARSET="+tb.charset)
+	}
+	if tb.collation != "" {
+		options = append(options, "COLLATE="+tb.collation)
+	}
+	if tb.rowFormat != "" {
+		options = append(options, "ROW_FORMAT="+tb.rowFormat)
+	}
+	if tb.comment != "" {
+		options = append(options, "COMMENT="+quoteLiteral(d, tb.comment))
+	}
+	return options
+}
+
+// collationClause renders the character set and collation of a column.
+// PostgreSQL collation names are case-sensitive, so they are always quoted.
+func collationClause(dialect Dialect, col *Column) string {
+	switch d := dialect.(type) {
+	case *MySQLDialect:
+		var clause string
+		if col.charset != "" {
+			clause += " CHARACTER SET " + col.charset
+		}
+		if collation := col.collation; collation != "" {
+			clause += " COLLATE " + collation
+		} else if col.charset == "" && col.existing != nil && col.existing.Collation != "" && mysqlTextType(d.GetDataType(col)) {
+			// MODIFY COLUMN would reset the collation to the table default.
+			clause += " COLLATE " + col.existing.Collation
+		}
+		return clause
+	case *PostgresDialect:
+		if col.collation != "" {
+			return " COLLATE " + quoteIdentifier(col.collation, '"', '"', nil, true)
+		}
+	case *SQLiteDialect:
+		if col.collation != "" {
+			return " COLLATE " + d.QuoteIdentifier(col.collation)
+		}
+	}
+	return ""
+}
+
+func mysqlTextType(dataType string) bool {
+	return strings.Contains(dataType, "CHAR") || strings.Contains(dataType, "TEXT") || strings.HasPrefix(dataType, "ENUM")
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,130 @@
+package olympian
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMySQLTableOptionsSQL(t *testing.T) {
+	tb := &TableBuilder{
+		tableName: "posts",
+		columns: []*Column{
+			{name: "id", dataType: "integer", primary: true},
+			{name: "slug", dataType: "string", charset: "ascii", collation: "ascii_bin"},
+			{name: "title", dataType: "string", collation: "utf8mb4_unicode_ci"},
+		},
+	}
+
+	sql := buildCreateTable(t, &MySQLDialect{}, tb)
+	for _, fragment := range []string{
+		"slug VARCHAR(255) CHARACTER SET ascii COLLATE ascii_bin NOT NULL",
+		"title VARCHAR(255) COLLATE utf8mb4_unicode_ci NOT NULL",
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;",
+	} {
+		if !strings.Contains(sql, fragment) {
+			t.Errorf("SQL should contain %s, got:\n%s", fragment, sql)
+		}
+	}
+
+	dialect := &MySQLDialect{Charset: "utf8mb4", Collation: "utf8mb4_0900_ai_ci", RowFormat: "dynamic"}
+	if sql := buildCreateTable(t, dialect, tb); !strings.Contains(sql, ") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci ROW_FORMAT=DYNAMIC;") {
+		t.Errorf("Expected the dialect defaults, got:\n%s", sql)
+	}
+
+	tb.Engine("MyISAM").Charset("latin1").Collation("latin1_swedish_ci").RowFormat("compact")
+	if sql := buildCreateTable(t, dialect, tb); !strings.Contains(sql, ") ENGINE=MyISAM DEFAULT CHARSET=latin1 COLLATE=latin1_swedish_ci ROW_FORMAT=COMPACT;") {
+		t.Errorf("Expected the table options to win, got:\n%s", sql)
+	}
+
+	tb = &TableBuilder{tableName: "posts"}
+	tb.Collation("utf8mb4_bin").RowFormat("compressed")
+	sqls, err := dialect.BuildModifyTable(tb)
+	if err != nil {
+		t.Fatalf("Failed to build SQL: %v", err)
+	}
+	if len(sqls) != 1 || sqls[0] != "ALTER TABLE posts COLLATE=utf8mb4_bin, ROW_FORMAT=COMPRESSED" {
+		t.Errorf("Unexpected SQL: %v", sqls)
+	}
+}
+
+func TestColumnCollationSQL(t *testing.T) {
+	tb := &TableBuilder{
+		tableName: "users",
+		columns: []*Column{
+			{name: "name", dataType: "string", collation: "en_US"},
+		},
+	}
+	if sql := buildCreateTable(t, &PostgresDialect{}, tb); !strings.Contains(sql, `name VARCHAR(255) COLLATE "en_US" NOT NULL`) {
+		t.Errorf("Expected a quoted collation, got:\n%s", sql)
+	}
+
+	tb.columns[0].change = true
+	sqls, err := (&PostgresDialect{}).BuildModifyTable(tb)
+	if err != nil {
+		t.Fatalf("Failed to build SQL: %v", err)
+	}
+	if len(sqls) != 1 || sqls[0] != `ALTER TABLE users ALTER COLUMN name TYPE VARCHAR(255) COLLATE "en_US" USING name::VARCHAR(255)` {
+		t.Errorf("Unexpected SQL: %v", sqls)
+	}
+
+	// MODIFY COLUMN keeps the current collation of text columns.
+	tb.columns = []*Column{
+		{name: "name", dataType: "string", change: true, existing: &ColumnInfo{Name: "name", Collation: "utf8mb4_bin"}},
+		{name: "code", dataType: "integer", change: true, existing: &ColumnInfo{Name: "code", Collation: "utf8mb4_bin"}},
+	}
+	sqls, err = (&MySQLDialect{}).BuildModifyTable(tb)
+	if err != nil {
+		t.Fatalf("Failed to build SQL: %v", err)
+	}
+	expected := "ALTER TABLE users MODIFY COLUMN name VARCHAR(255) COLLATE utf8mb4_bin NOT NULL, MODIFY COLUMN code INT NOT NULL"
+	if len(sqls) != 1 || sqls[0] != expected {
+		t.Errorf("Expected %s, got %v", expected, sqls)
+	}
+}
+
+func TestTableOptionsValidation(t *testing.T) {
+	tb := &TableBuilder{scope: scope{dialect: &PostgresDialect{}}, tableName: "users"}
+	tb.columns = []*Column{{name: "name", dataType: "string", charset: "utf8"}}
+	if err := tb.validateTableOptions(); err == nil {
+		t.Error("Expected an error for a column charset on PostgreSQL")
+	}
+
+	tb = &TableBuilder{scope: scope{dialect: &MySQLDialect{}}, tableName: "users"}
+	tb.RowFormat("tiny")
+	if err := tb.validateTableOptions(); err == nil {
+		t.Error("Expected an error for an unknown row format")
+	}
+}
+
+func TestColumnCollationSQLite(t *testing.T) {
+	db := setupTestDB(t)
+	defer func() { _ = db.Close() }()
+
+	SetDB(db, &SQLiteDialect{})
+
+	if err := Table("users").Create(func() {
+		Integer("id").Primary()
+		String("email").Unique().Collation("NOCASE")
+	}); err != nil {
+		t.Fatalf("Failed to create table: %v", err)
+	}
+	if _, err := db.Exec("INSERT INTO users (id, email) VALUES (1, 'ada@example.com')"); err != nil {
+		t.Fatalf("Failed to insert user: %v", err)
+	}
+	if _, err := db.Exec("INSERT INTO users (id, email) VALUES (2, 'ADA@example.com')"); err == nil {
+		t.Error("Expected NOCASE to make e-mails unique regardless of case")
+	}
+
+	if err := Table("users").Modify(func() {
+		String("email").Collation("BINARY").Change()
+	}); err != nil {
+		t.Fatalf("Failed to change collation: %v", err)
+	}
+	var createSQL string
+	if err := db.QueryRow("SELECT sql FROM sqlite_master WHERE name = 'users'").Scan(&createSQL); err != nil {
+		t.Fatalf("Failed to read table definition: %v", err)
+	}
+	if strings.Contains(createSQL, "NOCASE") || !strings.Contains(createSQL, "COLLATE BINARY") {
+		t.Errorf("Expected the collation to be replaced, got %s", createSQL)
+	}
+}
@@ -0,0 +1,197 @@
+package olympian
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+type Tenant struct {
+	Name    string
+	Driver  string
+	DSN     string
+	Dialect Dialect
+	Schema  string
+}
+
+type TenantResult struct {
+	Tenant  string
+	Pending []string
+	Err     error
+}
+
+type TenantResults []TenantResult
+
+// TenantRunner migrates tenants one after another. Migration bodies resolve
+// their connection through SetDB/GetDB, so two tenants cannot migrate at
+// the same time.
+type TenantRunner struct {
+	tenants []Tenant
+	failed  []Tenant
+}
+
+func DSNTenants(driver string, dialect Dialect, dsns map[string]string) []Tenant {
+	names := make([]string, 0, len(dsns))
+	for name := range dsns {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	tenants := make([]Tenant, 0, len(names))
+	for _, name := range names {
+		tenants = append(tenants, Tenant{
+			Name:    name,
+			Driver:  driver,
+			DSN:     dsns[name],
+			Dialect: dialect,
+		})
+	}
+	return tenants
+}
+
+func SchemaTenants(dsn string, schemas []string) []Tenant {
+	tenants := make([]Tenant, 0, len(schemas))
+	for _, schema := range schemas {
+		tenants = append(tenants, Tenant{
+			Name:    schema,
+			Driver:  "postgres",
+			DSN:     dsn,
+			Dialect: Postgres(),
+			Schema:  schema,
+		})
+	}
+	return tenants
+}
+
+func NewTenantRunner(tenants []Tenant) *TenantRunner {
+	return &TenantRunner{tenants: tenants}
+}
+
+func (r *TenantRunner) Migrate(migrations []Migration) TenantResults {
+	return r.each(func(m *Migrator, res *TenantResult) error {
+		return m.Migrate(migrations)
+	})
+}
+
+func (r *TenantRunner) Rollback(migrations []Migration, steps int) TenantResults {
+	return r.each(func(m *Migrator, res *TenantResult) error {
+		return m.Rollback(migrations, steps)
+	})
+}
+
+func (r *TenantRunner) Status(migrations []Migration) TenantResults {
+	return r.each(func(m *Migrator, res *TenantResult) error {
+		pending, err := m.Pending(migrations)
+		res.Pending = pending
+		return err
+	})
+}
+
+func (r *TenantRunner) Failed() []Tenant {
+	return append([]Tenant(nil), r.failed...)
+}
+
+// Resume returns a runner over the tenants that failed in the last run.
+func (r *TenantRunner) Resume() *TenantRunner {
+	return NewTenantRunner(r.Failed())
+}
+
+func (r *TenantRunner) each(op func(m *Migrator, res *TenantResult) error) TenantResults {
+	results := make(TenantResults, len(r.tenants))
+	db, dialect := currentDB()
+
+	var failed []Tenant
+	for i, tenant := range r.tenants {
+		results[i].Tenant = tenant.Name
+		results[i].Err = tenant.run(db, dialect, func(m *Migrator) error {
+			return op(m, &results[i])
+		})
+		if results[i].Err != nil {
+			failed = append(failed, tenant)
+		}
+	}
+	r.failed = failed
+
+	return results
+}
+
+// run migrates the tenant and afterwards points SetDB back at prev, so the
+// package-level functions do not keep using the closed tenant connection.
+func (t Tenant) run(prev *sql.DB, prevDialect Dialect, fn func(m *Migrator) error) error {
+	dsn := t.DSN
+	if t.Schema != "" {
+		dsn = WithSearchPath(dsn, t.Schema)
+	}
+
+	db, err := sql.Open(t.Driver, dsn)
+	if err != nil {
+		return fmt.Errorf("failed to connect: %w", err)
+	}
+	defer func() { _ = db.Close() }()
+	defer restoreDB(db, prev, prevDialect)
+
+	if t.Schema != "" {
+		if _, err := db.Exec(fmt.Sprintf("CREATE SCHEMA IF NOT EXISTS %s", t.Dialect.QuoteIdentifier(t.Schema))); err != nil {
+			return fmt.Errorf("failed to create schema %s: %w", t.Schema, err)
+		}
+	}
+
+	migrator := NewMigrator(db, t.Dialect)
+	if err := migrator.Init(); err != nil {
+		return fmt.Errorf("failed to initialize migrator: %w", err)
+	}
+
+	return fn(migrator)
+}
+
+// WithSearchPath adds a search_path to a PostgreSQL DSN, in URL or key=value
+// form, so every connection opened with it resolves unqualified names in
+// schemas, in order. Schemas may also be given as a comma-separated list.
+func WithSearchPath(dsn string, schemas ...string) string {
+	searchPath := strings.Join(searchPathNames(schemas), ",")
+
+	if strings.Contains(dsn, "://") {
+		sep := "?"
+		if strings.Contains(dsn, "?") {
+			sep = "&"
+		}
+		return dsn + sep + "search_path=" + url.QueryEscape(searchPath)
+	}
+	return strings.TrimSpace(dsn) + " search_path=" + searchPath
+}
+
+// searchPathNames splits comma-separated schema lists into their names.
+func searchPathNames(schemas []string) []string {
+	var names []string
+	for _, schema := range schemas {
+		for _, name := range strings.Split(schema, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				names = append(names, name)
+			}
+		}
+	}
+	return names
+}
+
+func (rs TenantResults) Failed() []string {
+	var names []string
+	for _, res := range rs {
+		if res.Err != nil {
+			names = append(names, res.Tenant)
+		}
+	}
+	return names
+}
+
+func (rs TenantResults) Err() error {
+	var errs []error
+	for _, res := range rs {
+		if res.Err != nil {
+			errs = append(errs, fmt.Errorf("tenant %s: %w", res.Tenant, res.Err))
+		}
+	}
+	return errors.Join(errs...)
+}
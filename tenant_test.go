@@ -0,0 +1,133 @@
+package olympian
+
+import (
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func tenantMigrations() []Migration {
+	return []Migration{
+		{
+			Name: "create_users_table",
+			Up: func() error {
+				return Table("users").Create(func() {
+					Uuid("id").Primary()
+					String("name")
+				})
+			},
+			Down: func() error {
+				return Table("users").Drop()
+			},
+		},
+	}
+}
+
+func TestTenantRunnerMigrate(t *testing.T) {
+	dir := t.TempDir()
+
+	tenants := DSNTenants("sqlite3", SQLite(), map[string]string{
+		"acme":     filepath.Join(dir, "acme.db"),
+		"globex":   filepath.Join(dir, "globex.db"),
+		"umbrella": filepath.Join(dir, "umbrella.db"),
+	})
+
+	app := setupTestDB(t)
+	defer func() { _ = app.Close() }()
+	SetDB(app, &SQLiteDialect{})
+
+	runner := NewTenantRunner(tenants)
+	results := runner.Migrate(tenantMigrations())
+	if err := results.Err(); err != nil {
+		t.Fatalf("Failed to migrate tenants: %v", err)
+	}
+
+	if db, _ := GetDB(); db != app {
+		t.Error("Expected the runner to restore the database set with SetDB")
+	}
+	if err := app.Ping(); err != nil {
+		t.Errorf("Expected the restored database to stay open: %v", err)
+	}
+
+	for _, tenant := range tenants {
+		db, err := sql.Open("sqlite3", tenant.DSN)
+		if err != nil {
+			t.Fatalf("Failed to open tenant %s: %v", tenant.Name, err)
+		}
+
+		var tableName string
+		err = db.QueryRow("SELECT name FROM sqlite_master WHERE type='table' AND name='users'").Scan(&tableName)
+		_ = db.Close()
+		if err != nil {
+			t.Errorf("Table was not created for tenant %s: %v", tenant.Name, err)
+		}
+	}
+
+	status := runner.Status(tenantMigrations())
+	for _, res := range status {
+		if len(res.Pending) != 0 {
+			t.Errorf("Expected no pending migrations for tenant %s, got %v", res.Tenant, res.Pending)
+		}
+	}
+}
+
+func TestTenantRunnerResume(t *testing.T) {
+	dir := t.TempDir()
+	missing := filepath.Join(dir, "missing")
+
+	tenants := DSNTenants("sqlite3", SQLite(), map[string]string{
+		"acme":   filepath.Join(dir, "acme.db"),
+		"globex": filepath.Join(missing, "globex.db"),
+	})
+
+	runner := NewTenantRunner(tenants)
+	results := runner.Migrate(tenantMigrations())
+
+	failed := results.Failed()
+	if len(failed) != 1 || failed[0] != "globex" {
+		t.Fatalf("Expected only globex to fail, got %v", failed)
+	}
+
+	if err := os.MkdirAll(missing, 0755); err != nil {
+		t.Fatalf("Failed to create tenant directory: %v", err)
+	}
+
+	resumed := runner.Resume()
+	results = resumed.Migrate(tenantMigrations())
+	if err := results.Err(); err != nil {
+		t.Fatalf("Failed to resume tenants: %v", err)
+	}
+
+	if len(results) != 1 || results[0].Tenant != "globex" {
+		t.Errorf("Expected resume to run only globex, got %v", results)
+	}
+
+	if len(resumed.Failed()) != 0 {
+		t.Errorf("Expected no failed tenants after resume, got %v", resumed.Failed())
+	}
+}
+
+func TestWithSearchPath(t *testing.T) {
+	tests := []struct {
+		dsn      string
+		expected string
+	}{
+		{"host=localhost dbname=app", "host=localhost dbname=app search_path=tenant_a"},
+		{"postgres://localhost/app", "postgres://localhost/app?search_path=tenant_a"},
+		{"postgres://localhost/app?sslmode=disable", "postgres://localhost/app?sslmode=disable&search_path=tenant_a"},
+	}
+
+	for _, tt := range tests {
+		if result := WithSearchPath(tt.dsn, "tenant_a"); result != tt.expected {
+			t.Errorf("Expected %s, got %s", tt.expected, result)
+		}
+	}
+
+	if result := WithSearchPath("host=localhost", "billing, public"); result != "host=localhost search_path=billing,public" {
+		t.Errorf("Unexpected DSN %s", result)
+	}
+	if result := WithSearchPath("postgres://localhost/app", "billing", "public"); result != "postgres://localhost/app?search_path=billing%2Cpublic" {
+		t.Errorf("Unexpected DSN %s", result)
+	}
+}
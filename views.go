@@ -0,0 +1,93 @@
+package olympian
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CreateView creates a view from a SELECT query.
+func CreateView(name, query string) error {
+	s := current()
+	sql := fmt.Sprintf("CREATE VIEW %s AS %s", s.dialect.QuoteIdentifier(name), query)
+	return s.execute(Operation{Kind: OpCreateView, Table: name, SQL: []string{sql}})
+}
+
+// CreateOrReplaceView creates a view or replaces its query. SQLite has no
+// CREATE OR REPLACE, so the view is dropped and created again. PostgreSQL
+// only replaces a view when the new query keeps the existing columns.
+func CreateOrReplaceView(name, query string) error {
+	s := current()
+	view := s.dialect.QuoteIdentifier(name)
+
+	var sqls []string
+	if _, isSQLite := s.dialect.(*SQLiteDialect); isSQLite {
+		sqls = []string{
+			fmt.Sprintf("DROP VIEW IF EXISTS %s", view),
+			fmt.Sprintf("CREATE VIEW %s AS %s", view, query),
+		}
+	} else {
+		sqls = []string{fmt.Sprintf("CREATE OR REPLACE VIEW %s AS %s", view, query)}
+	}
+	return s.execute(Operation{Kind: OpCreateView, Table: name, SQL: sqls})
+}
+
+// DropView drops a view if it exists.
+func DropView(name string) error {
+	s := current()
+	return s.execute(Operation{Kind: OpDropView, Table: name, SQL: []string{dropViewSQL(s.dialect, ViewInfo{Name: name})}})
+}
+
+// CreateMaterializedView creates a PostgreSQL materialized view and fills it
+// with the result of query.
+func CreateMaterializedView(name, query string) error {
+	s := current()
+	if err := materializedViewsSupported(s.dialect, name); err != nil {
+		return err
+	}
+	sql := fmt.Sprintf("CREATE MATERIALIZED VIEW %s AS %s", s.dialect.QuoteIdentifier(name), query)
+	return s.execute(Operation{Kind: OpCreateView, Table: name, SQL: []string{sql}})
+}
+
+// RefreshMaterializedView runs the query of a materialized view again and
+// replaces its rows.
+func RefreshMaterializedView(name string) error {
+	s := current()
+	if err := materializedViewsSupported(s.dialect, name); err != nil {
+		return err
+	}
+	sql := fmt.Sprintf("REFRESH MATERIALIZED VIEW %s", s.dialect.QuoteIdentifier(name))
+	return s.execute(Operation{Kind: OpRefreshView, Table: name, SQL: []string{sql}})
+}
+
+// DropMaterializedView drops a materialized view if it exists.
+func DropMaterializedView(name string) error {
+	s := current()
+	if err := materializedViewsSupported(s.dialect, name); err != nil {
+		return err
+	}
+	sql := dropViewSQL(s.dialect, ViewInfo{Name: name, Materialized: true})
+	return s.execute(Operation{Kind: OpDropView, Table: name, SQL: []string{sql}})
+}
+
+func materializedViewsSupported(dialect Dialect, name string) error {
+	if _, isPostgres := dialect.(*PostgresDialect); !isPostgres {
+		return fmt.Errorf("view %s: only PostgreSQL supports materialized views, use CreateView or a table instead", name)
+	}
+	return nil
+}
+
+func dropViewSQL(dialect Dialect, view ViewInfo) string {
+	if view.Materialized {
+		return fmt.Sprintf("DROP MATERIALIZED VIEW IF EXISTS %s", dialect.QuoteIdentifier(view.Name))
+	}
+	return fmt.Sprintf("DROP VIEW IF EXISTS %s", dialect.QuoteIdentifier(view.Name))
+}
+
+// sameViewDefinition compares view queries ignoring whitespace and a
+// trailing semicolon, which the databases add or drop when they store them.
+func sameViewDefinition(a, b string) bool {
+	normalize := func(s string) string {
+		return strings.Join(strings.Fields(strings.TrimSuffix(strings.TrimSpace(s), ";")), " ")
+	}
+	return normalize(a) == normalize(b)
+}
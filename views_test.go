@@ -0,0 +1,207 @@
+package olympian
+
+import (
+	"database/sql"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestViewSQL(t *testing.T) {
+	query := "SELECT user_id, SUM(total) AS total FROM orders GROUP BY user_id"
+	tests := []struct {
+		dialect  Dialect
+		expected []string
+	}{
+		{&PostgresDialect{}, []string{
+			"CREATE VIEW order_totals AS " + query,
+			"CREATE OR REPLACE VIEW order_totals AS " + query,
+			"DROP VIEW IF EXISTS order_totals",
+			"CREATE MATERIALIZED VIEW reporting.daily_sales AS " + query,
+			"REFRESH MATERIALIZED VIEW reporting.daily_sales",
+			"DROP MATERIALIZED VIEW IF EXISTS reporting.daily_sales",
+		}},
+		{&MySQLDialect{}, []string{
+			"CREATE VIEW order_totals AS " + query,
+			"CREATE OR REPLACE VIEW order_totals AS " + query,
+			"DROP VIEW IF EXISTS order_totals",
+		}},
+		{&SQLiteDialect{}, []string{
+			"CREATE VIEW order_totals AS " + query,
+			"DROP VIEW IF EXISTS order_totals",
+			"CREATE VIEW order_totals AS " + query,
+			"DROP VIEW IF EXISTS order_totals",
+		}},
+	}
+
+	for _, tt := range tests {
+		SetDB(nil, tt.dialect)
+		var ops []Operation
+		recorder = &ops
+		errs := []error{
+			CreateView("order_totals", query),
+			CreateOrReplaceView("order_totals", query),
+			DropView("order_totals"),
+		}
+		_, isPostgres := tt.dialect.(*PostgresDialect)
+		materialized := []error{
+			CreateMaterializedView("reporting.daily_sales", query),
+			RefreshMaterializedView("reporting.daily_sales"),
+			DropMaterializedView("reporting.daily_sales"),
+		}
+		recorder = nil
+
+		for _, err := range errs {
+			if err != nil {
+				t.Fatalf("%T: failed to build view operations: %v", tt.dialect, err)
+			}
+		}
+		for _, err := range materialized {
+			if isPostgres && err != nil {
+				t.Fatalf("%T: failed to build materialized view operations: %v", tt.dialect, err)
+			}
+			if !isPostgres && err == nil {
+				t.Errorf("%T: expected an error for materialized views", tt.dialect)
+			}
+		}
+
+		var sqls []string
+		for _, op := range ops {
+			sqls = append(sqls, op.SQL...)
+		}
+		if strings.Join(sqls, "\n") != strings.Join(tt.expected, "\n") {
+			t.Errorf("%T: expected:\n%s\ngot:\n%s", tt.dialect, strings.Join(tt.expected, "\n"), strings.Join(sqls, "\n"))
+		}
+		if ops[0].Kind != OpCreateView || ops[2].Kind != OpDropView {
+			t.Errorf("%T: unexpected operation kinds %s and %s", tt.dialect, ops[0].Kind, ops[2].Kind)
+		}
+	}
+}
+
+func TestSQLiteViews(t *testing.T) {
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "views.db"))
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	migrations := []Migration{
+		{
+			Name: "1_create_orders_table",
+			Up: func() error {
+				return Table("orders").Create(func() {
+					Integer("id").Primary()
+					Integer("user_id")
+					Integer("total")
+				})
+			},
+			Down: func() error {
+				return Table("orders").Drop()
+			},
+		},
+		{
+			Name: "2_create_order_totals_view",
+			Up: func() error {
+				return CreateView("order_totals", "SELECT user_id, SUM(total) AS total FROM orders GROUP BY user_id")
+			},
+			Down: func() error {
+				return DropView("order_totals")
+			},
+		},
+	}
+
+	migrator := NewMigrator(db, &SQLiteDialect{})
+	if err := migrator.Init(); err != nil {
+		t.Fatalf("Failed to initialize migrator: %v", err)
+	}
+	if err := migrator.Migrate(migrations); err != nil {
+		t.Fatalf("Failed to run migrations: %v", err)
+	}
+
+	if exists, err := HasView("order_totals"); err != nil || !exists {
+		t.Fatalf("Expected view order_totals to exist: %v", err)
+	}
+
+	if err := CreateOrReplaceView("order_totals", "SELECT user_id, COUNT(*) AS orders FROM orders GROUP BY user_id"); err != nil {
+		t.Fatalf("Failed to replace view: %v", err)
+	}
+	views, err := GetViews()
+	if err != nil {
+		t.Fatalf("Failed to get views: %v", err)
+	}
+	if len(views) != 1 || views[0].Definition != "SELECT user_id, COUNT(*) AS orders FROM orders GROUP BY user_id" {
+		t.Errorf("Unexpected views after replace: %+v", views)
+	}
+
+	if err := migrator.Fresh(migrations); err != nil {
+		t.Fatalf("Failed to run fresh: %v", err)
+	}
+	views, err = GetViews()
+	if err != nil {
+		t.Fatalf("Failed to get views: %v", err)
+	}
+	if len(views) != 1 || !strings.Contains(views[0].Definition, "SUM(total)") {
+		t.Errorf("Expected fresh to recreate the original view, got %+v", views)
+	}
+}
+
+func TestSchemaDiffViews(t *testing.T) {
+	expected := &Schema{
+		Tables: map[string]*TableInfo{},
+		Views: map[string]*ViewInfo{
+			"active_users": {Name: "active_users", Definition: "SELECT id FROM users WHERE active"},
+			"legacy_users": {Name: "legacy_users", Definition: "SELECT id FROM users"},
+			"daily_sales":  {Name: "daily_sales", Definition: "SELECT 1", Materialized: true},
+		},
+	}
+	actual := &Schema{
+		Tables: map[string]*TableInfo{},
+		Views: map[string]*ViewInfo{
+			"active_users": {Name: "active_users", Definition: "SELECT id\nFROM users\nWHERE active;"},
+			"daily_sales":  {Name: "daily_sales", Definition: "SELECT 2", Materialized: true},
+			"order_totals": {Name: "order_totals", Definition: "SELECT user_id FROM orders"},
+		},
+	}
+
+	diff := DiffSchemas(expected, actual)
+	if len(diff.AddedViews) != 1 || len(diff.RemovedViews) != 1 || len(diff.ChangedViews) != 1 {
+		t.Fatalf("Unexpected view diff: %s", diff)
+	}
+	if out := diff.String(); out != "+ view order_totals\n- view legacy_users\n~ materialized view daily_sales\n" {
+		t.Errorf("Unexpected diff output:\n%s", out)
+	}
+
+	source, err := diff.Migration("1_sync_views")
+	if err != nil {
+		t.Fatalf("Failed to generate migration: %v", err)
+	}
+	code := string(source)
+	for _, call := range []string{
+		"olympian.DropView(\"legacy_users\")",
+		"olympian.DropMaterializedView(\"daily_sales\")",
+		"olympian.CreateMaterializedView(\"daily_sales\", `SELECT 2`)",
+		"olympian.CreateView(\"order_totals\", `SELECT user_id FROM orders`)",
+		"olympian.CreateMaterializedView(\"daily_sales\", `SELECT 1`)",
+		"olympian.CreateView(\"legacy_users\", `SELECT id FROM users`)",
+	} {
+		if !strings.Contains(code, call) {
+			t.Errorf("Expected migration to contain %s:\n%s", call, code)
+		}
+	}
+	if strings.Index(code, "DropView(\"legacy_users\")") > strings.Index(code, "CreateView(\"order_totals\"") {
+		t.Errorf("Expected removed views to be dropped before added views are created:\n%s", code)
+	}
+}
+
+func TestUnqualifyMySQLView(t *testing.T) {
+	definition := "select `app`.`users`.`id` AS `id`,`app`.`users`.`name` AS `name` from `app`.`users` where (`app`.`users`.`active` = 1)"
+	expected := "select `users`.`id` AS `id`,`users`.`name` AS `name` from `users` where (`users`.`active` = 1)"
+	if got := unqualifyMySQLView(definition, "app"); got != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, got)
+	}
+
+	scratch := strings.ReplaceAll(definition, "`app`", "`app_scratch`")
+	if !sameViewDefinition(unqualifyMySQLView(definition, "app"), unqualifyMySQLView(scratch, "app_scratch")) {
+		t.Error("Expected views from differently named databases to compare equal")
+	}
+}